@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <session|project>",
+	Short: "Attach to an existing Zellij session",
+	Long: `Attach to an existing Zellij session, resurrecting it if it has exited.
+The argument is a session name, or a project whose session to attach to.
+Unlike start, a new session is never created.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSessions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return attachSession(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+}
+
+func attachSession(cmd *cobra.Command, name string) error {
+	if os.Getenv("ZELLIJ") != "" {
+		return ErrInsideZellij
+	}
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return ErrZellijMissing
+	}
+
+	sessionName := resolveSessionName(name)
+	if zellij.FindSession(sessionName) == nil {
+		return fmt.Errorf("no Zellij session named '%s'", sessionName)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Attaching to session %s...", styles.Bold.Render(sessionName))))
+	attachCmd := exec.Command("zellij", "attach", sessionName)
+	attachCmd.Stdin = os.Stdin
+	attachCmd.Stdout = os.Stdout
+	attachCmd.Stderr = os.Stderr
+
+	if err := attachCmd.Run(); err != nil {
+		return &SessionError{Session: sessionName, Err: fmt.Errorf("attaching: %w", err)}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Project names, template
+names, key=value settings used by a project and Zellij session names are
+completed as you type.
+
+Bash (needs the bash-completion package):
+
+  # Current shell
+  source <(zellijinator completion bash)
+
+  # Every new shell, on Linux
+  zellijinator completion bash > /etc/bash_completion.d/zellijinator
+  # Every new shell, on macOS with Homebrew
+  zellijinator completion bash > $(brew --prefix)/etc/bash_completion.d/zellijinator
+
+Zsh:
+
+  # Enable completion once, if it isn't already
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+  # Every new shell
+  zellijinator completion zsh > "${fpath[1]}/_zellijinator"
+
+Fish:
+
+  zellijinator completion fish > ~/.config/fish/completions/zellijinator.fish
+
+PowerShell:
+
+  zellijinator completion powershell | Out-String | Invoke-Expression
+
+Start a new shell for the completions to take effect.`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		switch args[0] {
+		case "bash":
+			return root.GenBashCompletionV2(cmd.OutOrStdout(), true)
+		case "zsh":
+			return root.GenZshCompletion(cmd.OutOrStdout())
+		case "fish":
+			return root.GenFishCompletion(cmd.OutOrStdout(), true)
+		case "powershell":
+			return root.GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+		}
+		return fmt.Errorf("unsupported shell %q (use bash, zsh, fish or powershell)", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// completeProjectNames completes the first argument with project names,
+// described by their root and tabs
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return projectCompletions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectArgs completes a project name followed by the key=value
+// settings that project's file refers to
+func completeProjectArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		if strings.Contains(toComplete, "=") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if strings.HasPrefix(toComplete, "@") {
+			return groupCompletions(), cobra.ShellCompDirectiveNoFileComp
+		}
+		return projectCompletions(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	path, err := config.Find(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return varCompletions(config.TemplateVarNames(data), args), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSessions completes Zellij session names
+func completeSessions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if strings.HasPrefix(toComplete, "@") {
+		return groupCompletions(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sessions, err := zellij.ListSessions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, session := range sessions {
+		if session.Exited {
+			completions = append(completions, session.Name+"\texited")
+		} else {
+			completions = append(completions, session.Name+"\trunning")
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateNames completes template names with their descriptions
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	templates, err := config.ListTemplates()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		completions = append(completions, tmpl.Name+"\t"+tmpl.Description)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateAnswers completes the key=value answers a template asks for
+func completeTemplateAnswers(templateName string, args []string) []string {
+	_, data, err := config.ReadTemplate(templateName)
+	if err != nil {
+		return nil
+	}
+	prompts, err := config.TemplatePrompts(data, config.TemplateValues{})
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		keys = append(keys, prompt.Key)
+	}
+	return varCompletions(keys, args)
+}
+
+// completeTags completes the tags used by any project
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	files, err := config.DiscoverProjects()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, file := range files {
+		project, err := config.LoadFile(file.Path, config.Vars{Partial: true})
+		if err != nil {
+			continue
+		}
+		for _, tag := range project.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectCompletions returns every project name, described by its root
+// and tabs. Files are only read, not loaded, so completion stays fast
+// however many projects there are.
+func projectCompletions() []string {
+	var completions []string
+	if localPath, _ := config.FindLocalProject(""); localPath != "" {
+		completions = append(completions, ".\tlocal project "+projectDescription(localPath))
+	}
+
+	files, err := config.DiscoverProjects()
+	if err != nil {
+		return completions
+	}
+	for _, file := range files {
+		completions = append(completions, file.Name+"\t"+projectDescription(file.Path))
+	}
+	return completions
+}
+
+// projectDescription describes the project file at path by its root and
+// tab names, read as plain YAML without composing or rendering it. The
+// path is used when that doesn't give either.
+func projectDescription(path string) string {
+	var summary struct {
+		Root string `yaml:"root"`
+		Tabs []struct {
+			Name string `yaml:"name"`
+		} `yaml:"tabs"`
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = yaml.Unmarshal(data, &summary)
+	}
+
+	var details []string
+	if err == nil {
+		if summary.Root != "" && !strings.Contains(summary.Root, "{{") {
+			details = append(details, collapseHome(config.ExpandPath(summary.Root)))
+		}
+		var tabs []string
+		for _, tab := range summary.Tabs {
+			if tab.Name != "" && !strings.Contains(tab.Name, "{{") {
+				tabs = append(tabs, tab.Name)
+			}
+		}
+		if len(tabs) > 0 {
+			details = append(details, "tabs: "+strings.Join(tabs, ", "))
+		}
+	}
+	if len(details) == 0 {
+		return collapseHome(path)
+	}
+	return strings.Join(details, " · ")
+}
+
+// groupCompletions completes @group names, described by their projects
+func groupCompletions() []string {
+	names, err := config.ListGroups()
+	if err != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, name := range names {
+		description := "group"
+		if group, err := config.LoadGroup(name); err == nil {
+			projects := make([]string, len(group.Projects))
+			for i, project := range group.Projects {
+				projects[i] = project.Name
+			}
+			description = "group: " + strings.Join(projects, ", ")
+		}
+		completions = append(completions, "@"+name+"\t"+description)
+	}
+	return completions
+}
+
+// varCompletions suggests key= for each key not already given in args
+func varCompletions(keys []string, args []string) []string {
+	given := config.ParseVars(args).Settings
+
+	var completions []string
+	for _, key := range keys {
+		if _, ok := given[key]; !ok {
+			completions = append(completions, key+"=")
+		}
+	}
+	return completions
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProjectCompletions(t *testing.T) {
+	configDir := testEnv(t)
+	writeFile(t, filepath.Join(configDir, "api.yaml"), testProject)
+	writeFile(t, filepath.Join(configDir, "work", "web.yml"), "root: ~/code/web\ntabs:\n  - name: editor\n  - name: server\n")
+	writeFile(t, filepath.Join(configDir, "tpl.yaml"), "# zellijinator: template\nroot: ~/{{ arg 0 }}\ntabs: [ {{ arg 1 }} ]\n")
+	writeFile(t, filepath.Join(configDir, "broken.yaml"), "root: [\n")
+
+	code, stdout, stderr := run(t, "", "__complete", "start", "")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d\nstderr: %s", code, stderr)
+	}
+
+	want := []string{
+		"api\t/tmp · tabs: main",
+		"work/web\t~/code/web · tabs: editor, server",
+		"tpl\t~/config/tpl.yaml",
+		"broken\t~/config/broken.yaml",
+	}
+	for _, line := range want {
+		if !strings.Contains(stdout, line+"\n") {
+			t.Errorf("completions don't include %q:\n%s", line, stdout)
+		}
+	}
+}
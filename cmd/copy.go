@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var copyCmd = &cobra.Command{
+	Use:     "copy <source> <destination>",
+	Aliases: []string{"cp"},
+	Short:   "Copy a zellijinator project",
+	Long: `Copy a project to a new name. The name in the copy is updated, along with
+session_name when it was the same as the old name. Comments and formatting
+are kept.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return copyProject(cmd, args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+}
+
+func copyProject(cmd *cobra.Command, src string, dst string) error {
+	_, data, err := renamedProjectData(src, dst)
+	if err != nil {
+		return err
+	}
+
+	dstPath := config.ProjectPath(dst)
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+	if err := os.WriteFile(dstPath, data, 0644); err != nil {
+		return fmt.Errorf("creating project file: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Copied %s to %s", styles.Bold.Render(src), styles.Bold.Render(dst))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(dstPath)))
+	return nil
+}
+
+// renamedProjectData reads the project src and returns its path and its
+// contents renamed to dst. It fails if dst already exists.
+func renamedProjectData(src string, dst string) (string, []byte, error) {
+	if src == "." || dst == "." {
+		return "", nil, fmt.Errorf("project-local files can't be copied or renamed")
+	}
+	if _, err := config.Find(dst); err == nil {
+		return "", nil, fmt.Errorf("project '%s' already exists at %s", dst, config.ProjectPath(dst))
+	}
+
+	srcPath, err := config.Find(src)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("reading project file: %w", err)
+	}
+
+	renamed, err := config.RewriteProjectName(data, filepath.Base(src), filepath.Base(dst))
+	if err != nil {
+		return "", nil, fmt.Errorf("updating %s: %w", srcPath, err)
+	}
+	return srcPath, renamed, nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	debugConfigOnly bool
+	debugLayoutOnly bool
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug [project] [args...] [key=value...]",
+	Short: "Show the merged configuration and generated layout",
+	Long: `Show a project exactly as start would use it: with runtime arguments
+rendered, extends/include files merged, and the Zellij layout generated from it.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, vars := splitProjectArgs(args)
+
+		if projectName == "" {
+			if hasLocalProject() {
+				projectName = "."
+			} else {
+				selected, err := selectProject("Select a project to debug:")
+				if err != nil {
+					return err
+				}
+				projectName = selected
+			}
+		}
+
+		return debugProject(cmd, projectName, vars)
+	},
+}
+
+func init() {
+	debugCmd.Flags().BoolVar(&debugConfigOnly, "config-only", false, "Only show the merged configuration")
+	debugCmd.Flags().BoolVar(&debugLayoutOnly, "layout-only", false, "Only show the generated layout")
+	rootCmd.AddCommand(debugCmd)
+}
+
+func debugProject(cmd *cobra.Command, name string, vars config.Vars) error {
+	projectPath, err := config.Find(name)
+	if err != nil {
+		return err
+	}
+
+	project, err := config.LoadFile(projectPath, vars)
+	if err != nil {
+		return err
+	}
+
+	if !debugLayoutOnly {
+		data, err := yaml.Marshal(project)
+		if err != nil {
+			return fmt.Errorf("encoding project: %w", err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), styles.Title.Render("Merged configuration"))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("# "+projectPath))
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	}
+
+	if !debugConfigOnly {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.Title.Render("Generated layout"))
+		if project.Layout != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Uses custom layout file %s", styles.Path.Render(project.Layout))))
+			return nil
+		}
+		fmt.Fprint(cmd.OutOrStdout(), zellij.GenerateLayout(project))
+	}
+	return nil
+}
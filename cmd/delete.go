@@ -9,8 +9,8 @@ import (
 
 	"github.com/dphaener/zellijinator/config"
 	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -19,26 +19,26 @@ var (
 )
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete [project]",
-	Short: "Delete a zellijinator project",
-	Long:  `Delete the specified zellijinator project configuration`,
-	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:               "delete [project]",
+	Short:             "Delete a zellijinator project",
+	Long:              `Delete the specified zellijinator project configuration`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectName string
 		
 		if len(args) == 0 {
 			// No project specified, show interactive selection
 			selected, err := selectProject("Select a project to delete:")
 			if err != nil {
-				fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("%v", err)))
-				os.Exit(1)
+				return err
 			}
 			projectName = selected
 		} else {
 			projectName = args[0]
 		}
 		
-		deleteProject(projectName)
+		return deleteProject(cmd, projectName)
 	},
 }
 
@@ -48,82 +48,64 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 }
 
-func deleteProject(name string) {
+func deleteProject(cmd *cobra.Command, name string) error {
 	// Get project path
-	projectPath := config.ProjectPath(name)
-	
-	// Check if project exists
-	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Project '%s' not found.", name)))
-		os.Exit(1)
-	}
-	
-	// Read project to get session name
-	data, err := os.ReadFile(projectPath)
+	projectPath, err := config.Find(name)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error reading project file: %v", err)))
-		os.Exit(1)
-	}
-	
-	var project config.Project
-	if err := yaml.Unmarshal(data, &project); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error parsing project file: %v", err)))
-		os.Exit(1)
+		return err
 	}
 	
-	sessionName := project.SessionName
-	if sessionName == "" {
-		sessionName = project.Name
+	// Read project to get session name. A broken file can still be deleted,
+	// it just can't tell us which session belongs to it.
+	sessionName := ""
+	project, err := config.LoadFile(projectPath, config.Vars{Partial: true})
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Could not load project file: %v", err)))
+	} else {
+		sessionName = project.SessionNameOrDefault()
 	}
 	
 	// Check if session is running
 	sessionRunning := false
-	checkCmd := exec.Command("zellij", "list-sessions", "-n")
-	output, err := checkCmd.CombinedOutput()
-	if err == nil && len(output) > 0 {
-		sessions := string(output)
-		for _, line := range strings.Split(sessions, "\n") {
-			if strings.TrimSpace(line) == sessionName {
-				sessionRunning = true
-				break
-			}
-		}
+	if sessionName != "" {
+		session := zellij.FindSession(sessionName)
+		sessionRunning = session != nil && !session.Exited
 	}
 	
 	// Confirm deletion if not forced
 	if !forceDelete {
-		fmt.Print(styles.Prompt.Render(fmt.Sprintf("Are you sure you want to delete project '%s'? (y/N): ", name)))
-		reader := bufio.NewReader(os.Stdin)
+		fmt.Fprint(cmd.OutOrStdout(), styles.Prompt.Render(fmt.Sprintf("Are you sure you want to delete project '%s'? (y/N): ", name)))
+		reader := bufio.NewReader(cmd.InOrStdin())
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
 		
 		if response != "y" && response != "yes" {
-			fmt.Println(styles.InfoMsg("Deletion cancelled."))
-			return
+			fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Deletion cancelled."))
+			return ErrCancelled
 		}
 	}
 	
 	// Kill session if requested and running
 	if killSession && sessionRunning {
-		fmt.Println(styles.InfoMsg(fmt.Sprintf("Killing Zellij session '%s'...", sessionName)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Killing Zellij session '%s'...", sessionName)))
 		killCmd := exec.Command("zellij", "kill-session", sessionName)
 		if err := killCmd.Run(); err != nil {
-			fmt.Fprintln(os.Stderr, styles.WarningMsg(fmt.Sprintf("Failed to kill session: %v", err)))
+			fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Failed to kill session: %v", err)))
 		}
 	} else if sessionRunning && !killSession {
-		fmt.Println(styles.WarningMsg(fmt.Sprintf("Zellij session '%s' is still running. Use -k flag to kill it.", sessionName)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg(fmt.Sprintf("Zellij session '%s' is still running. Use -k flag to kill it.", sessionName)))
 	}
 	
 	// Delete the project file
 	if err := os.Remove(projectPath); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error deleting project file: %v", err)))
-		os.Exit(1)
+		return fmt.Errorf("deleting project file: %w", err)
 	}
 	
-	fmt.Println(styles.SuccessMsg(fmt.Sprintf("Project '%s' deleted successfully.", name)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Project '%s' deleted successfully.", name)))
 	
 	// Clean up temporary layout files
 	tmpDir := os.TempDir()
 	layoutDir := strings.Join([]string{tmpDir, "zellijinator"}, string(os.PathSeparator))
 	cleanupOldLayouts(layoutDir)
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeleteKillsSession(t *testing.T) {
+	configDir := testEnv(t)
+	path := filepath.Join(configDir, "api.yaml")
+	writeFile(t, path, testProject)
+	fakeZellij(t, "api [Created 2h 5m ago]\\napi-gateway [Created 1m ago]\\n")
+
+	code, stdout, stderr := run(t, "", "delete", "api", "--force", "--kill")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("project file still exists: %v", err)
+	}
+	log, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), "zellij.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "kill-session api\n") {
+		t.Errorf("session wasn't killed, zellij was run with:\n%s", log)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+// detachedTimeout is how long to wait for a background session to appear
+const detachedTimeout = 10 * time.Second
+
+// What createDetachedSession did
+const (
+	detachedCreated     = "created"
+	detachedResurrected = "resurrected"
+	detachedRunning     = "already running"
+)
+
+var startDetached bool
+
+func init() {
+	for _, cmd := range []*cobra.Command{rootCmd, startCmd, hereCmd} {
+		cmd.Flags().BoolVarP(&startDetached, "detached", "d", false, "Create the session in the background without attaching to it")
+	}
+}
+
+// startSessionDetached creates project's session in the background and
+// tells the user how to attach to it. launched is called once the session
+// is running.
+func startSessionDetached(cmd *cobra.Command, project *config.Project, sessionName string, launched func(outcome string)) error {
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Starting session %s in the background...", styles.Bold.Render(sessionName))))
+
+	result, err := createDetachedSession(cmd, project, sessionName)
+	if err != nil {
+		return err
+	}
+	if result == detachedRunning {
+		launched(config.OutcomeAttached)
+	} else {
+		launched(config.OutcomeStarted)
+	}
+
+	switch result {
+	case detachedRunning:
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Session %s is already running", styles.Bold.Render(sessionName))))
+	case detachedResurrected:
+		fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Resurrected session %s in the background", styles.Bold.Render(sessionName))))
+	default:
+		fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Session %s is running in the background", styles.Bold.Render(sessionName))))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Attach with: %s", styles.Command.Render("zellijinator attach "+sessionName))))
+	return nil
+}
+
+// createDetachedSession creates project's session in the background and
+// waits for Zellij to list it. An exited session is resurrected and a
+// running one is left alone. It returns which of those it did.
+func createDetachedSession(cmd *cobra.Command, project *config.Project, sessionName string) (string, error) {
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return "", ErrZellijMissing
+	}
+
+	existing := zellij.FindSession(sessionName)
+	if existing != nil && !existing.Exited {
+		return detachedRunning, nil
+	}
+
+	result := detachedResurrected
+	args := []string{"attach", "--create-background", sessionName}
+	if existing == nil {
+		result = detachedCreated
+		layoutPath, err := writeLayout(cmd, project, sessionName)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "options", "--default-layout", layoutPath)
+	}
+
+	createCmd := exec.Command("zellij", args...)
+	createCmd.Dir = project.ResolvedRoot()
+	createCmd.Env = projectEnv(project)
+	if output, err := createCmd.CombinedOutput(); err != nil {
+		sessionErr := &SessionError{
+			Session: sessionName,
+			Err:     fmt.Errorf("starting in the background: %w", err),
+			Details: []string{fmt.Sprintf("Command was: %s", styles.Command.Render("zellij "+strings.Join(args, " ")))},
+		}
+		if text := strings.TrimSpace(string(output)); text != "" {
+			sessionErr.Details = append(sessionErr.Details, text)
+		}
+		return "", sessionErr
+	}
+
+	if err := waitForSession(sessionName, detachedTimeout); err != nil {
+		return "", &SessionError{Session: sessionName, Err: err}
+	}
+	return result, nil
+}
+
+// waitForSession polls list-sessions until the session is running
+func waitForSession(name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if session := zellij.FindSession(name); session != nil && !session.Exited {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("session did not appear within %s", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -11,26 +11,26 @@ import (
 )
 
 var editCmd = &cobra.Command{
-	Use:   "edit [project]",
-	Short: "Edit a zellijinator project",
-	Long:  `Open the specified project configuration in your default editor`,
-	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:               "edit [project]",
+	Short:             "Edit a zellijinator project",
+	Long:              `Open the specified project configuration in your default editor`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectName string
 		
 		if len(args) == 0 {
 			// No project specified, show interactive selection
 			selected, err := selectProject("Select a project to edit:")
 			if err != nil {
-				fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("%v", err)))
-				os.Exit(1)
+				return err
 			}
 			projectName = selected
 		} else {
 			projectName = args[0]
 		}
 		
-		editProject(projectName)
+		return editProject(cmd, projectName)
 	},
 }
 
@@ -38,15 +38,11 @@ func init() {
 	rootCmd.AddCommand(editCmd)
 }
 
-func editProject(name string) {
+func editProject(cmd *cobra.Command, name string) error {
 	// Get project path
-	projectPath := config.ProjectPath(name)
-	
-	// Check if project exists
-	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Project '%s' not found", name)))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg(fmt.Sprintf("Create it with: %s", styles.Command.Render(fmt.Sprintf("zellijinator new %s", name)))))
-		os.Exit(1)
+	projectPath, err := config.Find(name)
+	if err != nil {
+		return err
 	}
 	
 	// Open in editor (reuse the openInEditor function from new.go)
@@ -65,19 +61,20 @@ func editProject(name string) {
 	}
 
 	if editor == "" {
-		fmt.Println(styles.WarningMsg("No editor found. Please set EDITOR environment variable."))
-		fmt.Println(styles.InfoMsg("You can manually edit: " + styles.Path.Render(projectPath)))
-		return
+		fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg("No editor found. Please set EDITOR environment variable."))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("You can manually edit: "+styles.Path.Render(projectPath)))
+		return nil
 	}
 
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Opening %s in %s...", styles.Bold.Render(name), styles.Command.Render(editor))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Opening %s in %s...", styles.Bold.Render(name), styles.Command.Render(editor))))
 	editorCmd := exec.Command(editor, projectPath)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
 	editorCmd.Stderr = os.Stderr
 	
 	if err := editorCmd.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error opening editor: %v", err)))
-		fmt.Println(styles.InfoMsg("You can manually edit: " + styles.Path.Render(projectPath)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("You can manually edit: "+styles.Path.Render(projectPath)))
+		return fmt.Errorf("opening editor: %w", err)
 	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+)
+
+// Exit codes returned by Execute, so scripts can tell failures apart
+const (
+	ExitOK            = 0
+	ExitError         = 1
+	ExitNotFound      = 2
+	ExitInvalidConfig = 3
+	ExitZellijMissing = 4
+	ExitSessionFailed = 5
+	ExitCancelled     = 6
+)
+
+var (
+	// ErrCancelled is returned when the user backs out of a prompt or
+	// declines a confirmation. Commands print their own message first.
+	ErrCancelled = errors.New("cancelled")
+
+	// ErrZellijMissing is returned when the zellij binary can't be found
+	ErrZellijMissing = errors.New("zellij not found in PATH")
+
+	// ErrInsideZellij is returned when starting a session from inside one
+	ErrInsideZellij = errors.New("already inside a Zellij session")
+
+	// ErrValidationFailed is returned by validate after it has printed
+	// the problems it found
+	ErrValidationFailed = errors.New("validation failed")
+)
+
+// SessionError reports a Zellij session that couldn't be created or
+// attached to. Details are printed below the error to help debugging.
+type SessionError struct {
+	Session string
+	Err     error
+	Details []string
+}
+
+func (e *SessionError) Error() string {
+	return fmt.Sprintf("session %s: %v", e.Session, e.Err)
+}
+
+func (e *SessionError) Unwrap() error {
+	return e.Err
+}
+
+// GroupError reports the projects of a group that failed to start or
+// stop. Each failure has already been printed with the project.
+type GroupError struct {
+	Group  string
+	Action string
+	Failed []string
+	Total  int
+}
+
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("%d of %d projects in @%s failed to %s: %s", len(e.Failed), e.Total, e.Group, e.Action, strings.Join(e.Failed, ", "))
+}
+
+// exitCode maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	var missing *config.MissingVarError
+	var parseErr *config.ParseError
+	var validationErr *config.ValidationError
+	var cycleErr *config.CycleError
+	var nameErr *config.SessionNameError
+	var collisionErr *config.SessionCollisionError
+	var sessionErr *SessionError
+	var groupErr *GroupError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrCancelled):
+		return ExitCancelled
+	case errors.Is(err, config.ErrNotFound), errors.Is(err, config.ErrTemplateNotFound),
+		errors.Is(err, config.ErrGroupNotFound):
+		return ExitNotFound
+	case errors.As(err, &missing), errors.As(err, &parseErr),
+		errors.As(err, &validationErr), errors.As(err, &cycleErr),
+		errors.As(err, &nameErr), errors.As(err, &collisionErr),
+		errors.Is(err, ErrValidationFailed):
+		return ExitInvalidConfig
+	case errors.Is(err, ErrZellijMissing):
+		return ExitZellijMissing
+	case errors.Is(err, ErrInsideZellij), errors.As(err, &sessionErr), errors.As(err, &groupErr):
+		return ExitSessionFailed
+	default:
+		return ExitError
+	}
+}
+
+// reportError prints a styled message for an error returned by a command
+func reportError(w io.Writer, err error) {
+	var notFound *config.NotFoundError
+	var missing *config.MissingVarError
+	var parseErr *config.ParseError
+	var validationErr *config.ValidationError
+	var cycleErr *config.CycleError
+	var nameErr *config.SessionNameError
+	var collisionErr *config.SessionCollisionError
+	var sessionErr *SessionError
+	var groupErr *GroupError
+
+	switch {
+	case errors.Is(err, ErrCancelled), errors.Is(err, ErrValidationFailed):
+		// The command already said what was cancelled or invalid
+	case errors.As(err, &notFound):
+		if notFound.Name == "." {
+			fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("No %s found in this directory or any parent.", styles.Path.Render(config.LocalProjectFiles[0]))))
+		} else {
+			fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Project %s not found. Create it with: %s", styles.Bold.Render(notFound.Name), styles.Command.Render(fmt.Sprintf("zellijinator new %s", notFound.Name)))))
+		}
+	case errors.Is(err, config.ErrTemplateNotFound):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Template %v", err)))
+		fmt.Fprintln(w, styles.InfoMsg(fmt.Sprintf("See the available templates with: %s", styles.Command.Render("zellijinator templates list"))))
+	case errors.Is(err, config.ErrGroupNotFound):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Group %v", err)))
+		fmt.Fprintln(w, styles.InfoMsg(fmt.Sprintf("Groups are defined in %s", styles.Path.Render(collapseHome(config.GroupsDir())+"/<name>.yaml"))))
+	case errors.As(err, &missing):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Missing a value for %s", styles.Bold.Render(missing.Name))))
+		fmt.Fprintln(w, styles.InfoMsg(fmt.Sprintf("Pass it after the project name: %s", styles.Command.Render(missing.Name+"=<value>"))))
+	case errors.As(err, &parseErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Error parsing project file: %v", parseErr)))
+	case errors.As(err, &validationErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Invalid project file %s:", styles.Path.Render(validationErr.Path))))
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintln(w, styles.Subtle.Render("  - "+problem))
+		}
+	case errors.As(err, &cycleErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Error loading project file: %v", cycleErr)))
+	case errors.As(err, &nameErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("The session name %s %s.", styles.Bold.Render(nameErr.Name), nameErr.Problem)))
+		if nameErr.Suggestion != "" {
+			fmt.Fprintln(w, styles.InfoMsg(fmt.Sprintf("Set a shorter or simpler one in the project file, e.g. %s", styles.Command.Render(fmt.Sprintf("session_name: %s", nameErr.Suggestion)))))
+		}
+	case errors.As(err, &collisionErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("The session name %s is used by more than one project:", styles.Bold.Render(collisionErr.Session))))
+		for _, path := range collisionErr.Paths {
+			fmt.Fprintln(w, styles.Subtle.Render("  - "+collapseHome(path)))
+		}
+		fmt.Fprintln(w, styles.InfoMsg("Set a different session_name in one of them, so each project gets its own session."))
+	case errors.Is(err, ErrZellijMissing):
+		fmt.Fprintln(w, styles.ErrorMsg("Zellij is not installed or not in your PATH."))
+		fmt.Fprintln(w, styles.InfoMsg("Install it from https://zellij.dev/documentation/installation"))
+	case errors.Is(err, ErrInsideZellij):
+		fmt.Fprintln(w, styles.ErrorMsg("Already inside a Zellij session."))
+		fmt.Fprintln(w, styles.InfoMsg("Please exit the current session first (Ctrl+q) before starting a new one."))
+	case errors.As(err, &sessionErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("Error with Zellij session %s: %v", styles.Bold.Render(sessionErr.Session), sessionErr.Err)))
+		for _, detail := range sessionErr.Details {
+			fmt.Fprintln(w, styles.InfoMsg(detail))
+		}
+	case errors.As(err, &groupErr):
+		fmt.Fprintln(w, styles.ErrorMsg(fmt.Sprintf("%d of %d projects in %s failed to %s: %s", len(groupErr.Failed), groupErr.Total, styles.Bold.Render("@"+groupErr.Group), groupErr.Action, strings.Join(groupErr.Failed, ", "))))
+	default:
+		fmt.Fprintln(w, styles.ErrorMsg(err.Error()))
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testEnv isolates the config, state and PATH of a test. Projects are
+// written to the returned config directory, and zellij is not on PATH.
+func testEnv(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	configDir := filepath.Join(dir, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", dir)
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", configDir)
+	t.Setenv("ZELLIJINATOR_PATH", "")
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv("PATH", filepath.Join(dir, "bin"))
+	t.Setenv("ZELLIJ", "")
+	return configDir
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// run executes the root command with args and stdin, returning the exit
+// code and what was written to stdout and stderr
+func run(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetArgs(args)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	t.Cleanup(func() {
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	code := execute()
+	return code, stdout.String(), stderr.String()
+}
+
+const testProject = `name: api
+root: /tmp
+tabs:
+  - name: main
+    panes:
+      - commands: [ls]
+`
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, configDir string)
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "ok",
+			setup:  func(t *testing.T, configDir string) { writeFile(t, filepath.Join(configDir, "api.yaml"), testProject) },
+			args:   []string{"list", "--output", "names"},
+			code:   ExitOK,
+			stdout: "api\n",
+		},
+		{
+			name:   "error",
+			args:   []string{"list", "--output", "xml"},
+			code:   ExitError,
+			stderr: "unknown output format",
+		},
+		{
+			name:   "not found",
+			args:   []string{"start", "missing"},
+			code:   ExitNotFound,
+			stderr: "zellijinator new missing",
+		},
+		{
+			name: "invalid config",
+			setup: func(t *testing.T, configDir string) {
+				writeFile(t, filepath.Join(configDir, "bad.yaml"), "name: bad\ntabs:\n  - panes:\n      - split: diagonal\n")
+			},
+			args:   []string{"start", "bad"},
+			code:   ExitInvalidConfig,
+			stderr: `split must be horizontal or vertical, got "diagonal"`,
+		},
+		{
+			name:   "zellij missing",
+			setup:  func(t *testing.T, configDir string) { writeFile(t, filepath.Join(configDir, "api.yaml"), testProject) },
+			args:   []string{"start", "api"},
+			code:   ExitZellijMissing,
+			stderr: "Zellij is not installed",
+		},
+		{
+			name: "session failed",
+			setup: func(t *testing.T, configDir string) {
+				writeFile(t, filepath.Join(configDir, "api.yaml"), testProject)
+				t.Setenv("ZELLIJ", "0")
+			},
+			args:   []string{"start", "api"},
+			code:   ExitSessionFailed,
+			stderr: "Already inside a Zellij session",
+		},
+		{
+			name: "cancelled",
+			setup: func(t *testing.T, configDir string) {
+				repo := filepath.Join(t.TempDir(), "repo")
+				writeFile(t, filepath.Join(repo, ".zellijinator.yml"), "tabs:\n  - name: main\n")
+				cwd, _ := os.Getwd()
+				if err := os.Chdir(repo); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { os.Chdir(cwd) })
+			},
+			args:   []string{"start", "."},
+			stdin:  "n\n",
+			code:   ExitCancelled,
+			stdout: "Start cancelled.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir := testEnv(t)
+			if tt.setup != nil {
+				tt.setup(t, configDir)
+			}
+
+			code, stdout, stderr := run(t, tt.stdin, tt.args...)
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.code, stdout, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+// groupResult is the outcome of starting or stopping one group member
+type groupResult struct {
+	project string
+	session string
+	detail  string
+	err     error
+}
+
+// startGroup starts every project in the group in the background, a few
+// at a time, then attaches to the group's primary project. vars given on
+// the command line apply to every member, over the group's own.
+func startGroup(cmd *cobra.Command, name string, vars config.Vars) error {
+	if len(vars.Args) > 0 {
+		return fmt.Errorf("unexpected argument %q, groups only take key=value settings", vars.Args[0])
+	}
+	if opts := flagStartOptions(); opts.worktree != "" || opts.newInstance || opts.instance != "" {
+		return fmt.Errorf("--worktree, --new-instance and --as can't be used with a group")
+	}
+
+	group, err := config.LoadGroup(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Starting %d projects in %s...", len(group.Projects), styles.Bold.Render("@"+group.Name))))
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]groupResult)
+		slots   = make(chan struct{}, group.MaxConcurrency())
+	)
+	for _, member := range group.Projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			result := startGroupMember(cmd, member, vars)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[member.Name] = result
+			printGroupResult(cmd, result)
+		}()
+	}
+	wg.Wait()
+
+	var failed []string
+	for _, member := range group.Projects {
+		if results[member.Name].err != nil {
+			failed = append(failed, member.Name)
+		}
+	}
+
+	var groupErr error
+	if len(failed) > 0 {
+		groupErr = &GroupError{Group: group.Name, Action: "start", Failed: failed, Total: len(group.Projects)}
+	}
+
+	primary := results[group.PrimaryProject()]
+	if primary.err != nil {
+		return groupErr
+	}
+	if startDetached || os.Getenv("ZELLIJ") != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Attach with: %s", styles.Command.Render("zellijinator attach "+primary.session))))
+		return groupErr
+	}
+	if err := attachSession(cmd, primary.session); err != nil {
+		return err
+	}
+	return groupErr
+}
+
+// startGroupMember starts one project of a group in the background and
+// records it in the history
+func startGroupMember(cmd *cobra.Command, member config.GroupProject, vars config.Vars) groupResult {
+	result := groupResult{project: member.Name}
+
+	memberVars := member.StartVars(vars.Settings)
+
+	path, err := config.Find(member.Name)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	entry := config.HistoryEntry{
+		Project: member.Name,
+		Path:    path,
+		Time:    time.Now(),
+		Args:    memberVars.Args,
+		Vars:    memberVars.Settings,
+		Outcome: config.OutcomeStarted,
+	}
+
+	project, err := config.LoadFile(path, memberVars)
+	if err == nil {
+		err = checkSessionCollision(project)
+	}
+	if err == nil {
+		result.session = project.SessionNameOrDefault()
+		err = config.CheckSessionName(result.session)
+	}
+	if err == nil {
+		result.detail, err = createDetachedSession(cmd, project, result.session)
+		if result.detail == detachedRunning {
+			entry.Outcome = config.OutcomeAttached
+		}
+	}
+
+	recordHistory(cmd, entry, err)
+	result.err = err
+	return result
+}
+
+// stopGroup stops the sessions of every project in the group, and with
+// --all their instances and worktree sessions too
+func stopGroup(cmd *cobra.Command, name string) error {
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return ErrZellijMissing
+	}
+
+	group, err := config.LoadGroup(name)
+	if err != nil {
+		return err
+	}
+
+	records, err := collectProjects()
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]projectRecord, len(records))
+	for _, record := range records {
+		byPath[record.Path] = record
+	}
+
+	var failed []string
+	for _, member := range group.Projects {
+		result := stopGroupMember(member, byPath)
+		if result.err != nil {
+			failed = append(failed, member.Name)
+		}
+		printGroupResult(cmd, result)
+	}
+
+	if len(failed) > 0 {
+		return &GroupError{Group: group.Name, Action: "stop", Failed: failed, Total: len(group.Projects)}
+	}
+	return nil
+}
+
+func stopGroupMember(member config.GroupProject, records map[string]projectRecord) groupResult {
+	result := groupResult{project: member.Name}
+
+	path, err := config.Find(member.Name)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	vars := member.StartVars(nil)
+	vars.Partial = true
+	project, err := config.LoadFile(path, vars)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.session = project.SessionNameOrDefault()
+
+	var sessions []string
+	if session := zellij.FindSession(result.session); session != nil && !session.Exited {
+		sessions = append(sessions, result.session)
+	}
+	if record, ok := records[path]; ok && stopAll {
+		sessions = append(sessions, runningChildren(&record)...)
+	}
+	if len(sessions) == 0 {
+		result.detail = "not running"
+		return result
+	}
+
+	for _, sessionName := range sessions {
+		if output, err := exec.Command("zellij", "kill-session", sessionName).CombinedOutput(); err != nil {
+			result.err = fmt.Errorf("stopping %s: %v %s", sessionName, err, strings.TrimSpace(string(output)))
+			return result
+		}
+	}
+	result.detail = "stopped"
+	if len(sessions) > 1 {
+		result.detail += " " + strings.Join(sessions, ", ")
+	}
+	return result
+}
+
+// printGroupResult prints one line per group member
+func printGroupResult(cmd *cobra.Command, result groupResult) {
+	if result.err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s %s  %s\n", styles.Error.Render("✗"), styles.Bold.Render(result.project), result.err)
+		return
+	}
+	detail := result.detail
+	if result.session != "" && result.session != result.project {
+		detail += styles.Subtle.Render(" (session " + result.session + ")")
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "  %s %s  %s\n", styles.Success.Render("✓"), styles.Bold.Render(result.project), detail)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hereTemplate string
+	hereName     string
+	hereSave     bool
+)
+
+var hereCmd = &cobra.Command{
+	Use:   "here [key=value...]",
+	Short: "Start a session for the current directory without a project file",
+	Long: `Start or attach to a session for the current directory, built on the fly
+from a template. The project is named after the directory, or the repository
+when it has an origin remote, and its root is the current directory.
+
+The template is default_template from config.yaml, or "minimal" when it
+isn't set. Template prompts use their defaults unless answered with
+key=value arguments. Use --save to write the project to a file as well.`,
+	Args: cobra.ArbitraryArgs,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeTemplateAnswers(hereTemplateName(), args), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		answers := config.ParseVars(args)
+		if len(answers.Args) > 0 {
+			return fmt.Errorf("unexpected argument %q, template answers are given as key=value", answers.Args[0])
+		}
+		return startHere(cmd, answers.Settings)
+	},
+}
+
+func init() {
+	hereCmd.Flags().StringVarP(&hereTemplate, "template", "t", "", "Template to build the project from (default: default_template from config.yaml, else minimal)")
+	hereCmd.Flags().StringVar(&hereName, "name", "", "Project name (default: the repository or directory name)")
+	hereCmd.Flags().BoolVar(&hereSave, "save", false, "Save the project to the config directory before starting it")
+	hereCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+	rootCmd.AddCommand(hereCmd)
+}
+
+func startHere(cmd *cobra.Command, answers map[string]string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	name := hereName
+	if name == "" {
+		name = directoryProjectName(cwd)
+	}
+
+	templateName := hereTemplateName()
+	_, data, err := config.ReadTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := config.RenderProjectTemplate(data, config.TemplateValues{Name: name, Root: collapseHome(cwd), Answers: answers})
+	if err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+
+	if hereSave {
+		projectPath := config.ProjectPath(name)
+		if _, err := config.Find(name); err == nil {
+			return fmt.Errorf("project '%s' already exists at %s", name, projectPath)
+		}
+		if err := os.MkdirAll(filepath.Dir(projectPath), 0755); err != nil {
+			return fmt.Errorf("creating project directory: %w", err)
+		}
+		if err := os.WriteFile(projectPath, rendered, 0644); err != nil {
+			return fmt.Errorf("creating project file: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Saved project %s to %s", styles.Bold.Render(name), styles.Path.Render(projectPath))))
+		return StartProject(cmd, name, config.ParseVars(nil))
+	}
+
+	// Load through a temporary file so the project is read exactly as a
+	// saved one would be
+	tmpDir := filepath.Join(os.TempDir(), "zellijinator")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(tmpDir, "here-*.yaml")
+	if err != nil {
+		return fmt.Errorf("creating temp project: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(rendered); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing temp project: %w", err)
+	}
+	tmpFile.Close()
+
+	project, err := config.LoadFile(tmpFile.Name(), config.ParseVars(nil))
+	if err != nil {
+		return err
+	}
+	return startProjectSession(cmd, project, func(string) {})
+}
+
+// hereTemplateName returns the template here builds its project from
+func hereTemplateName() string {
+	if hereTemplate != "" {
+		return hereTemplate
+	}
+	if settings, err := config.LoadSettings(); err == nil && settings.DefaultTemplate != "" {
+		return settings.DefaultTemplate
+	}
+	return "minimal"
+}
+
+// directoryProjectName names a project after the repository's origin
+// remote, or after dir when there isn't one
+func directoryProjectName(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "config", "--get", "remote.origin.url").Output()
+	if err == nil {
+		remote := strings.TrimSuffix(strings.TrimSpace(string(out)), "/")
+		remote = strings.TrimSuffix(remote, ".git")
+		// Handles both https://host/org/repo and git@host:org/repo
+		if i := strings.LastIndexAny(remote, "/:"); i >= 0 {
+			remote = remote[i+1:]
+		}
+		if remote != "" {
+			return remote
+		}
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit   int
+	historyProject string
+	historyOutput  string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recently started projects",
+	Long: `Show the projects that were started, newest first, with the arguments
+they were given and whether the session was created, attached to or failed.
+
+The history is kept in $XDG_STATE_HOME/zellijinator/history.jsonl
+(~/.local/state/zellijinator by default).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showHistory(cmd)
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Number of entries to show (0 for all)")
+	historyCmd.Flags().StringVarP(&historyProject, "project", "p", "", "Only show entries for this project")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "", "Output format: json")
+	historyCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	historyCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"json"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(historyCmd)
+}
+
+func showHistory(cmd *cobra.Command) error {
+	if historyOutput != "" && historyOutput != "json" {
+		return fmt.Errorf("unknown output format %q (use json)", historyOutput)
+	}
+
+	entries, err := config.ReadHistory()
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+
+	// Newest first
+	var shown []config.HistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if historyProject != "" && entries[i].Project != historyProject {
+			continue
+		}
+		shown = append(shown, entries[i])
+		if historyLimit > 0 && len(shown) == historyLimit {
+			break
+		}
+	}
+
+	if historyOutput == "json" {
+		if shown == nil {
+			shown = []config.HistoryEntry{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(shown)
+	}
+
+	if len(shown) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.Dim.Render("No projects started yet."))
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.Title.Render("History"))
+	for _, entry := range shown {
+		name := entry.Project
+		if entry.Worktree != "" {
+			name += "@" + entry.Worktree
+		}
+		if entry.Instance != "" {
+			name += "-" + entry.Instance
+		}
+		if entry.Local {
+			name += " (local)"
+		}
+		line := fmt.Sprintf("  %s  %s", styles.Subtle.Render(entry.Time.Local().Format("2006-01-02 15:04")), styles.Bold.Render(name))
+		if args := entry.CommandArgs(); len(args) > 0 {
+			line += " " + strings.Join(args, " ")
+		}
+
+		switch entry.Outcome {
+		case config.OutcomeFailed:
+			line += "  " + styles.Error.Render(entry.Outcome)
+		default:
+			line += "  " + styles.Success.Render(entry.Outcome)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+
+		if entry.Error != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("    "+entry.Error))
+		}
+	}
+	return nil
+}
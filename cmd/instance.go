@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newInstance    bool
+	instanceSuffix string
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{rootCmd, startCmd} {
+		cmd.Flags().BoolVar(&newInstance, "new-instance", false, "Start another copy of the project as <session>-2, <session>-3, ...")
+		cmd.Flags().StringVar(&instanceSuffix, "as", "", "Start a copy of the project as <session>-<suffix>")
+		cmd.MarkFlagsMutuallyExclusive("new-instance", "as")
+	}
+}
+
+// applyInstance renames project's session for --new-instance and --as, so
+// a copy of the project runs beside the original. It returns the suffix
+// used, empty when neither option was given.
+func applyInstance(project *config.Project, opts startOptions) (string, error) {
+	base := project.SessionNameOrDefault()
+
+	switch {
+	case opts.instance != "":
+		project.SessionName = base + "-" + opts.instance
+		return opts.instance, nil
+	case opts.newInstance:
+		sessions, err := zellij.ListSessions()
+		if err != nil {
+			return "", err
+		}
+		taken := make(map[string]bool, len(sessions))
+		for _, session := range sessions {
+			taken[session.Name] = true
+		}
+		// Exited sessions keep their names, so skip those too
+		for n := 2; ; n++ {
+			suffix := fmt.Sprint(n)
+			if !taken[base+"-"+suffix] {
+				project.SessionName = base + "-" + suffix
+				return suffix, nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var lastCmd = &cobra.Command{
+	Use:   "last",
+	Short: "Start or attach to the most recently started project",
+	Long: `Start the project that was started most recently, with the same
+arguments, or attach to its session if it is still running. Failed starts
+are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return startLast(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lastCmd)
+}
+
+func startLast(cmd *cobra.Command) error {
+	entries, err := config.ReadHistory()
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Outcome == config.OutcomeFailed {
+			continue
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Last started: %s", styles.Bold.Render(entry.Project))))
+		opts := startOptions{worktree: entry.Worktree, instance: entry.Instance}
+
+		// Local files are found by path, since "." depends on the directory
+		path := entry.Path
+		if entry.Local {
+			if _, err := os.Stat(path); err != nil {
+				return &config.NotFoundError{Name: path}
+			}
+		} else if path, err = config.Find(entry.Project); err != nil {
+			return err
+		}
+		return startProjectFile(cmd, entry.Project, path, entry.StartVars(), opts)
+	}
+
+	return fmt.Errorf("no projects have been started yet")
+}
@@ -1,132 +1,444 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/zellij"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 var (
 	showActive bool
+	showExited bool
+	listTags   []string
+	listOutput string
+	listRecent bool
 )
 
+// Session states reported by list
+const (
+	stateActive = "active"
+	stateExited = "exited"
+	stateNone   = "none"
+)
+
+// projectRecord is one project as printed by list
+type projectRecord struct {
+	Name    string   `json:"name" yaml:"name"`
+	Path    string   `json:"path" yaml:"path"`
+	Session string   `json:"session,omitempty" yaml:"session,omitempty"`
+	Root    string   `json:"root,omitempty" yaml:"root,omitempty"`
+	Tabs    []string `json:"tabs" yaml:"tabs"`
+	Tags    []string `json:"tags" yaml:"tags"`
+	State   string   `json:"state" yaml:"state"`
+	// Local is set for the project-local file found from the current directory
+	Local bool `json:"local,omitempty" yaml:"local,omitempty"`
+	// Error is set when the project file couldn't be loaded
+	Error    string   `json:"error,omitempty" yaml:"error,omitempty"`
+	Shadowed []string `json:"shadowed,omitempty" yaml:"shadowed,omitempty"`
+	// Worktrees are the sessions started in git worktrees of the project
+	Worktrees []childSession `json:"worktrees,omitempty" yaml:"worktrees,omitempty"`
+	// Instances are extra copies started with --new-instance or --as
+	Instances []childSession `json:"instances,omitempty" yaml:"instances,omitempty"`
+	// LastStarted is when the project was last started, from the history
+	LastStarted *time.Time `json:"last_started,omitempty" yaml:"last_started,omitempty"`
+
+	searchPath string
+	sessionAge time.Duration
+	untrusted  bool
+}
+
+// childSession is a session derived from a project's session name:
+// <session>@<worktree> or <session>-<instance>
+type childSession struct {
+	Name    string `json:"name" yaml:"name"`
+	Session string `json:"session" yaml:"session"`
+	State   string `json:"state" yaml:"state"`
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all zellijinator projects",
-	Long:  `List all available zellijinator project configurations`,
-	Run: func(cmd *cobra.Command, args []string) {
-		listProjects()
+	Long: `List all available zellijinator project configurations.
+
+Use --output json, yaml or names for scripts, shell completion and status
+bars. The names format prints one project per line, "." for a local project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listProjects(cmd)
 	},
 }
 
 func init() {
-	listCmd.Flags().BoolVarP(&showActive, "active", "a", false, "Show active Zellij sessions")
+	listCmd.Flags().BoolVarP(&showActive, "active", "a", false, "Only show projects with a running Zellij session")
+	listCmd.Flags().BoolVarP(&showExited, "exited", "e", false, "Only show projects with an exited Zellij session")
+	listCmd.Flags().StringSliceVarP(&listTags, "tag", "t", nil, "Only show projects with this tag (repeatable)")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", "", "Output format: json, yaml or names")
+	listCmd.Flags().BoolVarP(&listRecent, "recent", "r", false, "Sort by most recently started")
+	listCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"json", "yaml", "names"}, cobra.ShellCompDirectiveNoFileComp))
+	listCmd.RegisterFlagCompletionFunc("tag", completeTags)
 	rootCmd.AddCommand(listCmd)
 }
 
-func listProjects() {
+func listProjects(cmd *cobra.Command) error {
+	switch listOutput {
+	case "", "json", "yaml", "names":
+	default:
+		return fmt.Errorf("unknown output format %q (use json, yaml or names)", listOutput)
+	}
+
+	records, err := collectProjects()
+	if err != nil {
+		return err
+	}
+	records = filterProjects(records)
+	if listRecent {
+		sortByRecent(records)
+	}
+
+	switch listOutput {
+	case "json":
+		if records == nil {
+			records = []projectRecord{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "yaml":
+		encoder := yaml.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent(2)
+		if err := encoder.Encode(records); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "names":
+		for _, record := range records {
+			if record.Local {
+				fmt.Fprintln(cmd.OutOrStdout(), ".")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), record.Name)
+			}
+		}
+		return nil
+	}
+
+	printProjects(cmd, records)
+	return nil
+}
+
+// collectProjects loads the local project and every project in the search
+// paths, along with the state of their sessions
+func collectProjects() ([]projectRecord, error) {
+	projects, err := config.DiscoverProjects()
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	sessions := make(map[string]zellij.Session)
+	if list, err := zellij.ListSessions(); err == nil {
+		for _, session := range list {
+			sessions[session.Name] = session
+		}
+	}
+
+	lastStarted := config.LastStarted()
+
+	var records []projectRecord
+	if localPath, _ := config.FindLocalProject(""); localPath != "" {
+		record := loadRecord(".", localPath, sessions)
+		record.Local = true
+		record.setLastStarted(lastStarted)
+		records = append(records, record)
+	}
+	for _, file := range projects {
+		record := loadRecord(file.Name, file.Path, sessions)
+		record.Shadowed = file.Shadowed
+		record.searchPath = file.SearchPath
+		record.setLastStarted(lastStarted)
+		records = append(records, record)
+	}
+
+	// Sessions named after a project's session are its worktrees and
+	// instances, unless another project's session name fits them better
+	owners := sessionOwners(sessions, records)
+	instances := config.StartedInstances()
+	for i := range records {
+		record := &records[i]
+		if record.Session == "" {
+			continue
+		}
+		record.Worktrees = childSessions(sessions, owners, record.Session+"@", func(string) bool { return true })
+		record.Instances = childSessions(sessions, owners, record.Session+"-", func(suffix string) bool {
+			return isInstanceNumber(suffix) || instances[record.Path][suffix]
+		})
+	}
+	return records, nil
+}
+
+// sessionOwners maps each session to the project session it is named
+// after: the longest one it equals or extends with - or @. With projects
+// api and api-gateway, api-gateway-2 belongs to api-gateway.
+func sessionOwners(sessions map[string]zellij.Session, records []projectRecord) map[string]string {
+	owners := make(map[string]string)
+	for name := range sessions {
+		for _, record := range records {
+			owner := record.Session
+			if owner == "" || len(owner) <= len(owners[name]) {
+				continue
+			}
+			if name == owner || strings.HasPrefix(name, owner+"-") || strings.HasPrefix(name, owner+"@") {
+				owners[name] = owner
+			}
+		}
+	}
+	return owners
+}
+
+// childSessions returns the sessions named prefix followed by a suffix
+// that match accepts, sorted by name. Sessions owned by another project's
+// session are skipped.
+func childSessions(sessions map[string]zellij.Session, owners map[string]string, prefix string, match func(suffix string) bool) []childSession {
+	parent := prefix[:len(prefix)-1]
+
+	var children []childSession
+	for name, session := range sessions {
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok || suffix == "" || owners[name] != parent || !match(suffix) {
+			continue
+		}
+		state := stateActive
+		if session.Exited {
+			state = stateExited
+		}
+		children = append(children, childSession{Name: suffix, Session: name, State: state})
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
+	return children
+}
+
+// isInstanceNumber reports whether suffix is one --new-instance picks
+func isInstanceNumber(suffix string) bool {
+	n, err := strconv.Atoi(suffix)
+	return err == nil && n >= 2 && strconv.Itoa(n) == suffix
+}
+
+func (r *projectRecord) setLastStarted(lastStarted map[string]time.Time) {
+	if started, ok := lastStarted[r.Path]; ok {
+		r.LastStarted = &started
+	}
+}
+
+// recency is the latest of when the project was last started and when its
+// session was created, zero if neither is known
+func (r *projectRecord) recency() time.Time {
+	var recent time.Time
+	if r.LastStarted != nil {
+		recent = *r.LastStarted
+	}
+	if r.State != stateNone && r.sessionAge > 0 {
+		if created := time.Now().Add(-r.sessionAge); created.After(recent) {
+			recent = created
+		}
+	}
+	return recent
+}
+
+func loadRecord(name string, path string, sessions map[string]zellij.Session) projectRecord {
+	record := projectRecord{Name: name, Path: path, State: stateNone, Tabs: []string{}, Tags: []string{}}
+
+	proj, err := config.LoadFile(path, config.Vars{Partial: true})
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+
+	if name == "." {
+		record.Name = proj.Name
+		trusted, _ := config.IsTrusted(proj)
+		record.untrusted = !trusted
+	}
+	record.Session = proj.SessionNameOrDefault()
+	record.Root = proj.ResolvedRoot()
+	for _, tab := range proj.Tabs {
+		record.Tabs = append(record.Tabs, tab.Name)
+	}
+	record.Tags = append(record.Tags, proj.Tags...)
+
+	if session, ok := sessions[record.Session]; ok {
+		record.State = stateActive
+		record.sessionAge = session.Age
+		if session.Exited {
+			record.State = stateExited
+		}
+	}
+
+	return record
+}
+
+// filterProjects applies the --active, --exited and --tag filters. A
+// project must have every tag given.
+func filterProjects(records []projectRecord) []projectRecord {
+	if !showActive && !showExited && len(listTags) == 0 {
+		return records
+	}
+
+	var filtered []projectRecord
+	for _, record := range records {
+		if showActive && !showExited && record.State != stateActive {
+			continue
+		}
+		if showExited && !showActive && record.State != stateExited {
+			continue
+		}
+		if showActive && showExited && record.State == stateNone {
+			continue
+		}
+		if !hasTags(record.Tags, listTags) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+func hasTags(tags []string, wanted []string) bool {
+	for _, want := range wanted {
+		found := false
+		for _, tag := range tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func printProjects(cmd *cobra.Command, records []projectRecord) {
 	// Define styles
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("86")).
 		MarginBottom(1)
-	
+
 	projectStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("212"))
-	
+
 	activeStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("42")).
 		Background(lipgloss.Color("235")).
 		Padding(0, 1)
-	
+
+	exitedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Background(lipgloss.Color("235")).
+		Padding(0, 1)
+
 	infoStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
 		PaddingLeft(2)
-	
+
 	errorStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("196"))
-	
+
 	dimStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240"))
 
-	projects, err := config.ListProjects()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error listing projects: %v", err)))
-		os.Exit(1)
-	}
-
-	if len(projects) == 0 {
-		fmt.Println(dimStyle.Render("No projects found."))
-		fmt.Println(dimStyle.Render("Create a new project with: zellijinator new <project-name>"))
+	if len(records) == 0 {
+		if showActive || showExited || len(listTags) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), dimStyle.Render("No projects match."))
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), dimStyle.Render("No projects found."))
+		fmt.Fprintln(cmd.OutOrStdout(), dimStyle.Render("Create a new project with: zellijinator new <project-name>"))
 		return
 	}
 
-	// Get active sessions
-	activeSessions := make(map[string]bool)
-	checkCmd := exec.Command("zellij", "list-sessions", "-n")
-	output, err := checkCmd.CombinedOutput()
-	if err == nil && len(output) > 0 {
-		sessions := string(output)
-		for _, line := range strings.Split(sessions, "\n") {
-			session := strings.TrimSpace(line)
-			if session != "" {
-				activeSessions[session] = true
-			}
+	printedTitle := false
+	for _, record := range records {
+		if record.Local {
+			fmt.Fprintln(cmd.OutOrStdout(), titleStyle.Render("Local Project"))
+		} else if !printedTitle {
+			fmt.Fprintln(cmd.OutOrStdout(), titleStyle.Render("Zellijinator Projects"))
+			printedTitle = true
 		}
-	}
-
-	fmt.Println(titleStyle.Render("Zellijinator Projects"))
 
-	for _, project := range projects {
-		// Read project file to get session name and info
-		projectPath := config.ProjectPath(project)
-		data, err := os.ReadFile(projectPath)
-		if err != nil {
-			fmt.Printf("  %s %s\n", 
-				projectStyle.Render(project),
-				errorStyle.Render("(error reading config)"))
+		name := record.Name
+		if record.Error != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s %s\n",
+				projectStyle.Render(name),
+				errorStyle.Render("(error loading config)"))
 			continue
 		}
 
-		var proj config.Project
-		if err := yaml.Unmarshal(data, &proj); err != nil {
-			fmt.Printf("  %s %s\n", 
-				projectStyle.Render(project),
-				errorStyle.Render("(error parsing config)"))
-			continue
+		// Format project name with status
+		projectLine := "  " + projectStyle.Render(name)
+		switch record.State {
+		case stateActive:
+			projectLine += " " + activeStyle.Render("ACTIVE")
+		case stateExited:
+			projectLine += " " + exitedStyle.Render("EXITED")
 		}
-
-		sessionName := proj.SessionName
-		if sessionName == "" {
-			sessionName = proj.Name
+		if record.untrusted {
+			projectLine += " " + errorStyle.Render("(not yet trusted)")
 		}
+		fmt.Fprintln(cmd.OutOrStdout(), projectLine)
 
-		// Format project name with status
-		projectLine := "  " + projectStyle.Render(project)
-		if activeSessions[sessionName] {
-			projectLine += " " + activeStyle.Render("ACTIVE")
+		if record.Local {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("File: %s", record.Path)))
 		}
-		fmt.Println(projectLine)
-		
 		// Show additional info
-		if proj.Root != "" {
-			fmt.Println(infoStyle.Render(fmt.Sprintf("Root: %s", proj.Root)))
+		if record.Root != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("Root: %s", record.Root)))
+		}
+		if len(record.Tabs) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("Tabs: %s", strings.Join(record.Tabs, ", "))))
 		}
-		if len(proj.Tabs) > 0 {
-			tabNames := make([]string, 0, len(proj.Tabs))
-			for _, tab := range proj.Tabs {
-				tabNames = append(tabNames, tab.Name)
+		if len(record.Tags) > 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("Tags: %s", strings.Join(record.Tags, ", "))))
+		}
+		for _, group := range []struct {
+			title    string
+			children []childSession
+		}{{"Worktrees:", record.Worktrees}, {"Instances:", record.Instances}} {
+			if len(group.children) == 0 {
+				continue
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(group.title))
+			for _, child := range group.children {
+				line := infoStyle.Render("  " + child.Session)
+				if child.State == stateActive {
+					line += " " + activeStyle.Render("ACTIVE")
+				} else {
+					line += " " + exitedStyle.Render("EXITED")
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), line)
 			}
-			fmt.Println(infoStyle.Render(fmt.Sprintf("Tabs: %s", strings.Join(tabNames, ", "))))
 		}
-		fmt.Println() // Add spacing between projects
+		if record.Local {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render("Start with: zellijinator start ."))
+		} else if record.searchPath != filepath.Clean(config.ConfigDir()) {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("From: %s", record.Path)))
+		}
+		for _, shadowed := range record.Shadowed {
+			fmt.Fprintln(cmd.OutOrStdout(), infoStyle.Render(fmt.Sprintf("Overrides: %s", shadowed)))
+		}
+		fmt.Fprintln(cmd.OutOrStdout()) // Add spacing between projects
 	}
-}
\ No newline at end of file
+}
@@ -4,56 +4,214 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 
+	"github.com/charmbracelet/huh"
 	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/detect"
 	"github.com/dphaener/zellijinator/internal/styles"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	newInteractive  bool
+	newFromTemplate bool
+	newDetect       bool
+	newTemplate     string
+	newRoot         string
 )
 
 var newCmd = &cobra.Command{
-	Use:   "new [project]",
+	Use:   "new [project] [--detect [--root dir] | --template name [key=value...]]",
 	Short: "Create a new zellijinator project",
-	Long:  `Create a new zellijinator project configuration file`,
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Create a new zellijinator project configuration file.
+
+By default the file is created from an annotated sample and opened in your
+editor. Use --interactive to build the project step by step instead.
+
+--template creates the project from a template: a built-in one (minimal,
+web-app, monorepo) or your own from the templates directory of the config
+directory (see "zellijinator templates"). Answers to the template's prompts
+can be given as key=value arguments; anything not given is asked for.
+--from-template is shorthand for --template minimal.
+
+  zellijinator new shop --template web-app dev_command="bin/dev"
+
+--detect inspects the project root (--root, the current directory by default)
+for package.json scripts, a Makefile, docker-compose services, a Procfile,
+go.mod, Cargo.toml and .git, and proposes a project with an editor tab, a
+test-runner pane and a tab per service. The proposal is shown for
+confirmation before it is written.
+
+  zellijinator new api --detect --root ~/src/api`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeNewArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		projectName := args[0]
-		createNewProject(projectName)
+		rest := args[1:]
+
+		if newDetect {
+			if len(rest) > 0 {
+				return fmt.Errorf("unexpected argument %q, give the directory with --root", rest[0])
+			}
+			dir := newRoot
+			if dir == "" {
+				dir = "."
+			}
+			return detectNewProject(cmd, projectName, dir)
+		}
+
+		if newFromTemplate {
+			newTemplate = "minimal"
+		}
+		if newTemplate != "" {
+			answers := config.ParseVars(rest)
+			if len(answers.Args) > 0 {
+				return fmt.Errorf("unexpected argument %q, template answers are given as key=value", answers.Args[0])
+			}
+			return createFromTemplate(cmd, projectName, newTemplate, answers.Settings)
+		}
+
+		if len(rest) > 0 {
+			return fmt.Errorf("unexpected argument %q", rest[0])
+		}
+		return createNewProject(cmd, projectName)
 	},
 }
 
 func init() {
+	newCmd.Flags().BoolVarP(&newInteractive, "interactive", "i", false, "Build the project with an interactive wizard")
+	newCmd.Flags().BoolVar(&newFromTemplate, "from-template", false, "Start from the minimal template instead of the annotated sample")
+	newCmd.Flags().StringVarP(&newTemplate, "template", "t", "", "Create the project from a template")
+	newCmd.Flags().StringVar(&newRoot, "root", "", "Project root for templates and --detect (default: current directory)")
+	newCmd.Flags().BoolVar(&newDetect, "detect", false, "Propose a project from the contents of the project root")
+	newCmd.MarkFlagsMutuallyExclusive("interactive", "from-template", "template", "detect")
+	newCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+	newCmd.RegisterFlagCompletionFunc("root", cobra.FixedCompletions(nil, cobra.ShellCompDirectiveFilterDirs))
 	rootCmd.AddCommand(newCmd)
 }
 
-func createNewProject(name string) {
+// completeNewArgs completes the answers for --template after the new
+// project's name
+func completeNewArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	case newTemplate != "":
+		return completeTemplateAnswers(newTemplate, args[1:]), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	case newFromTemplate:
+		return completeTemplateAnswers("minimal", args[1:]), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// detectNewProject proposes a project from the contents of dir and writes
+// it once the user confirms
+func detectNewProject(cmd *cobra.Command, name string, dir string) error {
+	if _, err := config.Find(name); err == nil {
+		return fmt.Errorf("project '%s' already exists at %s", name, config.ProjectPath(name))
+	}
+
+	scan, err := detect.Directory(config.ExpandPath(dir))
+	if err != nil {
+		return fmt.Errorf("inspecting %s: %w", dir, err)
+	}
+
+	for _, warning := range scan.Warnings {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Skipped %s", warning)))
+	}
+	if len(scan.Findings) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg(fmt.Sprintf("Nothing recognised in %s; proposing a basic project.", styles.Path.Render(scan.Dir))))
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Found in %s:", styles.Path.Render(scan.Dir))))
+		for _, finding := range scan.Findings {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render(fmt.Sprintf("  %s - %s", finding.File, finding.Detail)))
+		}
+	}
+
+	project := scan.Project(name)
+	project.Root = collapseHome(project.Root)
+
+	projectPath := config.ProjectPath(name)
+	confirmed, err := confirmProject(project, projectPath)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Project not written."))
+		return ErrCancelled
+	}
+
+	if err := config.EnsureConfigDir(); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := config.Save(name, project); err != nil {
+		return fmt.Errorf("creating project file: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created new zellijinator project: %s", styles.Bold.Render(name))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(projectPath)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Start it with: %s", styles.Command.Render(fmt.Sprintf("zellijinator start %s", name)))))
+	return nil
+}
+
+func createNewProject(cmd *cobra.Command, name string) error {
 	// Ensure config directory exists
 	if err := config.EnsureConfigDir(); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error creating config directory: %v", err)))
-		os.Exit(1)
+		return fmt.Errorf("creating config directory: %w", err)
 	}
 
 	// Check if project already exists
 	projectPath := config.ProjectPath(name)
-	if _, err := os.Stat(projectPath); err == nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Project '%s' already exists", name)))
-		fmt.Fprintln(os.Stderr, styles.Subtle.Render(fmt.Sprintf("  Location: %s", projectPath)))
-		os.Exit(1)
+	if _, err := config.Find(name); err == nil {
+		return fmt.Errorf("project '%s' already exists at %s", name, projectPath)
+	}
+
+	// Namespaced projects (e.g. work/api) live in subdirectories
+	if err := os.MkdirAll(filepath.Dir(projectPath), 0755); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+
+	if newInteractive {
+		project, err := runProjectWizard(name)
+		if err != nil {
+			return err
+		}
+
+		confirmed, err := confirmProject(project, projectPath)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Project not written."))
+			return ErrCancelled
+		}
+
+		if err := config.Save(name, project); err != nil {
+			return fmt.Errorf("creating project file: %w", err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created new zellijinator project: %s", styles.Bold.Render(name))))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(projectPath)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Start it with: %s", styles.Command.Render(fmt.Sprintf("zellijinator start %s", name)))))
+		return nil
 	}
 
 	// Create the config file with sample content
 	if err := os.WriteFile(projectPath, []byte(getSampleTemplate(name)), 0644); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error creating project file: %v", err)))
-		os.Exit(1)
+		return fmt.Errorf("creating project file: %w", err)
 	}
 
-	fmt.Println(styles.SuccessMsg(fmt.Sprintf("Created new zellijinator project: %s", styles.Bold.Render(name))))
-	fmt.Println(styles.InfoMsg("Config file: " + styles.Path.Render(projectPath)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created new zellijinator project: %s", styles.Bold.Render(name))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(projectPath)))
 
 	// Open in editor
-	openInEditor(projectPath)
+	openInEditor(cmd, projectPath)
+	return nil
 }
 
-func openInEditor(path string) {
+func openInEditor(cmd *cobra.Command, path string) {
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = os.Getenv("VISUAL")
@@ -69,21 +227,105 @@ func openInEditor(path string) {
 	}
 
 	if editor == "" {
-		fmt.Println(styles.WarningMsg("No editor found. Please set EDITOR environment variable."))
-		fmt.Println(styles.InfoMsg("You can manually edit: " + styles.Path.Render(path)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg("No editor found. Please set EDITOR environment variable."))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("You can manually edit: "+styles.Path.Render(path)))
 		return
 	}
 
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Opening in %s...", styles.Command.Render(editor))))
-	cmd := exec.Command(editor, path)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Opening in %s...", styles.Command.Render(editor))))
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
 	
-	if err := cmd.Run(); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error opening editor: %v", err)))
-		fmt.Println(styles.InfoMsg("You can manually edit: " + styles.Path.Render(path)))
+	if err := editorCmd.Run(); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.ErrorMsg(fmt.Sprintf("Error opening editor: %v", err)))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("You can manually edit: "+styles.Path.Render(path)))
+	}
+}
+
+// createFromTemplate renders the named template into a new project,
+// asking for any template prompts not answered on the command line
+func createFromTemplate(cmd *cobra.Command, name string, templateName string, answers map[string]string) error {
+	if _, err := config.Find(name); err == nil {
+		return fmt.Errorf("project '%s' already exists at %s", name, config.ProjectPath(name))
+	}
+
+	_, data, err := config.ReadTemplate(templateName)
+	if err != nil {
+		return err
+	}
+
+	root := newRoot
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	values := config.TemplateValues{Name: name, Root: collapseHome(config.ExpandPath(root)), Answers: answers}
+
+	prompts, err := config.TemplatePrompts(data, values)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+	if len(prompts) > 0 {
+		if err := askTemplatePrompts(prompts, values.Answers); err != nil {
+			return err
+		}
+	}
+
+	rendered, err := config.RenderProjectTemplate(data, values)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+
+	// Make sure the result is a valid project before writing it. Its own
+	// {{ }} placeholders, if it is a template too, render as empty values.
+	projectPath := config.ProjectPath(name)
+	check, err := config.RenderTemplate(projectPath, rendered, config.Vars{Partial: true})
+	if err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+	var project config.Project
+	if err := yaml.Unmarshal(check, &project); err != nil {
+		return fmt.Errorf("template %s produced invalid YAML: %w", templateName, err)
+	}
+	project.Path = projectPath
+	if err := project.Validate(); err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(projectPath), 0755); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+	if err := os.WriteFile(projectPath, rendered, 0644); err != nil {
+		return fmt.Errorf("creating project file: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created new zellijinator project %s from template %s", styles.Bold.Render(name), styles.Bold.Render(templateName))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(projectPath)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Start it with: %s", styles.Command.Render(fmt.Sprintf("zellijinator start %s", name)))))
+	return nil
+}
+
+// askTemplatePrompts asks for each prompt and stores the answers
+func askTemplatePrompts(prompts []config.TemplatePrompt, answers map[string]string) error {
+	values := make([]string, len(prompts))
+	fields := make([]huh.Field, len(prompts))
+	for i, prompt := range prompts {
+		values[i] = prompt.Default
+		fields[i] = huh.NewInput().
+			Title(prompt.Question).
+			Description(prompt.Key).
+			Value(&values[i])
+	}
+
+	if err := huh.NewForm(huh.NewGroup(fields...)).WithTheme(formTheme()).Run(); err != nil {
+		return formError("template prompts", err)
+	}
+
+	for i, prompt := range prompts {
+		answers[prompt.Key] = values[i]
 	}
+	return nil
 }
 
 func getSampleTemplate(projectName string) string {
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+var renameSession bool
+
+var renameCmd = &cobra.Command{
+	Use:     "rename <old> <new>",
+	Aliases: []string{"mv"},
+	Short:   "Rename a zellijinator project",
+	Long: `Rename a project. The file is renamed in the directory it was found in and
+its name is updated, along with session_name when it was the same as the
+old name. Comments and formatting are kept.
+
+If the project's session is running it keeps its old name unless
+--session is given.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return renameProject(cmd, args[0], args[1])
+	},
+}
+
+func init() {
+	renameCmd.Flags().BoolVarP(&renameSession, "session", "s", false, "Also rename the running Zellij session")
+	rootCmd.AddCommand(renameCmd)
+}
+
+func renameProject(cmd *cobra.Command, oldName string, newName string) error {
+	oldPath, data, err := renamedProjectData(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	oldSession := ""
+	if project, err := config.LoadFile(oldPath, config.Vars{Partial: true}); err == nil {
+		oldSession = project.SessionNameOrDefault()
+	}
+
+	// Keep the file in the search path it came from
+	ext := filepath.Ext(oldPath)
+	dir := strings.TrimSuffix(oldPath, filepath.FromSlash(oldName)+ext)
+	newPath := filepath.Join(dir, filepath.FromSlash(newName)+ext)
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("project '%s' already exists at %s", newName, newPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("creating project directory: %w", err)
+	}
+	if err := os.WriteFile(newPath, data, 0644); err != nil {
+		return fmt.Errorf("creating project file: %w", err)
+	}
+	if err := os.Remove(oldPath); err != nil {
+		return fmt.Errorf("removing old project file: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Renamed %s to %s", styles.Bold.Render(oldName), styles.Bold.Render(newName))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Config file: "+styles.Path.Render(newPath)))
+
+	newSession := ""
+	if project, err := config.LoadFile(newPath, config.Vars{Partial: true}); err == nil {
+		newSession = project.SessionNameOrDefault()
+	}
+	if oldSession == "" || newSession == "" || oldSession == newSession {
+		return nil
+	}
+
+	session := zellij.FindSession(oldSession)
+	if session == nil || session.Exited {
+		return nil
+	}
+
+	if !renameSession {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg(fmt.Sprintf("Zellij session '%s' is still running under its old name. Use --session to rename it.", oldSession)))
+		return nil
+	}
+
+	renameCmd := exec.Command("zellij", "--session", oldSession, "action", "rename-session", newSession)
+	if output, err := renameCmd.CombinedOutput(); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Failed to rename session: %v %s", err, strings.TrimSpace(string(output)))))
+		return nil
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Renamed Zellij session '%s' to '%s'", oldSession, newSession)))
+	return nil
+}
@@ -1,16 +1,16 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/dphaener/zellijinator/config"
 	"github.com/spf13/cobra"
 )
 
 var cfgFile string
 
 var rootCmd = &cobra.Command{
-	Use:   "zellijinator [project]",
+	Use:   "zellijinator [project] [args...] [key=value...]",
 	Short: "A CLI tool to manage Zellij sessions",
 	Long: `Zellijinator is a CLI tool to manage Zellij sessions with pre-configured layouts.
 	
@@ -18,39 +18,54 @@ Similar to tmuxinator for tmux, zellijinator allows you to:
 - Create and manage session configurations
 - Start sessions with pre-defined layouts
 - List, edit, and delete session configurations`,
-	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeProjectArgs,
+	// Errors are reported by Execute, which also picks the exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, vars := splitProjectArgs(args)
+
 		// If a project name is provided, start it
-		if len(args) == 1 {
-			StartProject(args[0])
-		} else {
-			// No project specified, show interactive selection
-			selected, err := selectProject("Select a project to start:")
-			if err != nil {
-				// If selection failed, show help
-				cmd.Help()
-				return
-			}
-			StartProject(selected)
+		if projectName != "" {
+			return StartProject(cmd, projectName, vars)
+		} else if hasLocalProject() {
+			return StartProject(cmd, ".", vars)
+		}
+
+		// No project specified, show interactive selection
+		// If there is nothing to select, show help
+		if projects, err := config.ListProjects(); err != nil || len(projects) == 0 {
+			return cmd.Help()
 		}
+		return startFromPicker(cmd, vars)
 	},
 }
 
+// Execute runs the root command. Errors returned by commands are printed
+// here and mapped to the exit codes defined in errors.go.
 func Execute() {
+	if code := execute(); code != ExitOK {
+		os.Exit(code)
+	}
+}
+
+// execute runs the root command and returns the exit code
+func execute() int {
 	err := rootCmd.Execute()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		reportError(rootCmd.ErrOrStderr(), err)
 	}
+	return exitCode(err)
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.zellijinator/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is config.yaml in the zellijinator config directory)")
 	rootCmd.Version = version
 	rootCmd.SetVersionTemplate("{{.Version}}\n")
 }
 
 func initConfig() {
-	// Config initialization will be implemented later
+	config.SettingsFile = cfgFile
 }
\ No newline at end of file
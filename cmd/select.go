@@ -1,58 +1,262 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/picker"
 	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
 )
 
-// selectProject presents an interactive list of projects using huh
+var pickerMode string
+
+// Actions offered by the picker when starting a project
+var startActions = []picker.Action{
+	{Key: "enter", Name: "start"},
+	{Key: "ctrl+e", Name: "edit"},
+	{Key: "ctrl+x", Name: "stop"},
+	{Key: "ctrl+d", Name: "delete"},
+}
+
+var pickerPreviewCmd = &cobra.Command{
+	Use:    "picker-preview <project>",
+	Short:  "Print the picker preview for a project",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		records, err := collectProjects()
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if pickerName(record) == args[0] {
+				fmt.Fprintln(cmd.OutOrStdout(), projectPreview(record))
+				return nil
+			}
+		}
+		return &config.NotFoundError{Name: args[0]}
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&pickerMode, "picker", "", "Project picker to use: builtin or fzf (default from config.yaml, else builtin)")
+	rootCmd.RegisterFlagCompletionFunc("picker", cobra.FixedCompletions([]string{"builtin", "fzf"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(pickerPreviewCmd)
+}
+
+// selectProject presents an interactive list of projects
 // Returns the selected project name or an error
 func selectProject(prompt string) (string, error) {
-	// Get list of projects
-	projects, err := config.ListProjects()
+	name, _, err := pickProject(prompt, nil)
+	return name, err
+}
+
+// startFromPicker lets the user pick a project and start, edit, stop or
+// delete it
+func startFromPicker(cmd *cobra.Command, vars config.Vars) error {
+	name, action, err := pickProject("Select a project to start:", startActions)
 	if err != nil {
-		return "", fmt.Errorf("error listing projects: %v", err)
-	}
-	
-	if len(projects) == 0 {
-		return "", fmt.Errorf("no projects found. Create one with: zellijinator new <project-name>")
-	}
-	
-	// Create options for the select
-	options := make([]huh.Option[string], len(projects))
-	for i, project := range projects {
-		options[i] = huh.NewOption(project, project)
-	}
-	
-	// Use huh to select a project
-	var selected string
-	
-	// Create a custom theme that matches our styling
-	theme := huh.ThemeCharm()
-	theme.Focused.Base = theme.Focused.Base.BorderForeground(styles.Info.GetForeground())
-	theme.Focused.Title = styles.Title
-	
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title(prompt).
-				Options(options...).
-				Value(&selected),
-		),
-	).WithTheme(theme)
-	
-	err = form.Run()
+		return err
+	}
+
+	switch action {
+	case "edit":
+		return editProject(cmd, name)
+	case "stop":
+		return stopSession(cmd, name)
+	case "delete":
+		return deleteProject(cmd, name)
+	}
+	return StartProject(cmd, name, vars)
+}
+
+// pickProject shows the picker and returns the chosen project and the name
+// of the action used to choose it
+func pickProject(prompt string, actions []picker.Action) (string, string, error) {
+	records, err := collectProjects()
+	if err != nil {
+		return "", "", fmt.Errorf("error listing projects: %v", err)
+	}
+
+	if len(records) == 0 {
+		return "", "", fmt.Errorf("no projects found. Create one with: zellijinator new <project-name>")
+	}
+
+	sortByRecent(records)
+
+	mode := pickerMode
+	if mode == "" {
+		if settings, err := config.LoadSettings(); err == nil {
+			mode = settings.Picker
+		}
+	}
+
+	switch mode {
+	case "", "builtin":
+		return pickBuiltin(prompt, records, actions)
+	case "fzf":
+		return pickFzf(prompt, records, actions)
+	}
+	return "", "", fmt.Errorf("unknown picker %q (use builtin or fzf)", mode)
+}
+
+func pickBuiltin(prompt string, records []projectRecord, actions []picker.Action) (string, string, error) {
+	items := make([]picker.Item, len(records))
+	for i, record := range records {
+		items[i] = picker.Item{Name: pickerName(record), Preview: projectPreview(record)}
+		if record.State != stateNone {
+			items[i].Badge = record.State
+		}
+	}
+
+	result, err := picker.Run(prompt, items, actions)
 	if err != nil {
-		// User cancelled selection (e.g., pressed Ctrl-C)
-		return "", fmt.Errorf("selection cancelled")
+		if errors.Is(err, picker.ErrCancelled) {
+			return "", "", fmt.Errorf("selection %w", ErrCancelled)
+		}
+		return "", "", err
+	}
+	return result.Item.Name, result.Action, nil
+}
+
+// pickFzf runs fzf over the projects, with the preview printed by the
+// hidden picker-preview command and actions bound through --expect
+func pickFzf(prompt string, records []projectRecord, actions []picker.Action) (string, string, error) {
+	if _, err := exec.LookPath("fzf"); err != nil {
+		return "", "", fmt.Errorf("fzf not found in PATH")
 	}
-	
-	if selected == "" {
-		return "", fmt.Errorf("no project selected")
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", "", err
+	}
+
+	var input strings.Builder
+	for _, record := range records {
+		line := pickerName(record)
+		if record.State != stateNone {
+			line += "\t" + strings.ToUpper(record.State)
+		}
+		input.WriteString(line + "\n")
 	}
-	
-	return selected, nil
-}
\ No newline at end of file
+
+	header := prompt
+	var keys, help []string
+	for _, action := range actions {
+		if action.Key == "enter" {
+			continue
+		}
+		key := strings.ReplaceAll(action.Key, "+", "-")
+		keys = append(keys, key)
+		help = append(help, fmt.Sprintf("%s %s", key, action.Name))
+	}
+	if len(help) > 0 {
+		header += "  " + strings.Join(help, " • ")
+	}
+
+	args := []string{
+		"--delimiter", "\t",
+		"--prompt", "> ",
+		"--header", header,
+		"--preview", fmt.Sprintf("%s picker-preview {1}", config.ShellQuote(exe)),
+	}
+	if len(keys) > 0 {
+		args = append(args, "--expect", strings.Join(keys, ","))
+	}
+
+	fzf := exec.Command("fzf", args...)
+	fzf.Stdin = strings.NewReader(input.String())
+	fzf.Stderr = os.Stderr
+	var out bytes.Buffer
+	fzf.Stdout = &out
+
+	if err := fzf.Run(); err != nil {
+		// fzf exits 1 when nothing matched and 130 when cancelled
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 1 || exitErr.ExitCode() == 130) {
+			return "", "", fmt.Errorf("selection %w", ErrCancelled)
+		}
+		return "", "", fmt.Errorf("running fzf: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	key := ""
+	if len(keys) > 0 {
+		key, lines = lines[0], lines[1:]
+	}
+	if len(lines) == 0 || lines[0] == "" {
+		return "", "", fmt.Errorf("no project selected")
+	}
+	name, _, _ := strings.Cut(lines[0], "\t")
+
+	action := ""
+	for _, a := range actions {
+		if strings.ReplaceAll(a.Key, "+", "-") == key || (key == "" && a.Key == "enter") {
+			action = a.Name
+		}
+	}
+	return name, action, nil
+}
+
+// sortByRecent orders projects by when they were last started or their
+// session created, most recent first. Projects never started keep their
+// order at the end.
+func sortByRecent(records []projectRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].recency().After(records[j].recency())
+	})
+}
+
+// pickerName is the name a project is started with
+func pickerName(record projectRecord) string {
+	if record.Local {
+		return "."
+	}
+	return record.Name
+}
+
+// projectPreview describes a project for the picker's preview panel
+func projectPreview(record projectRecord) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Bold.Render(record.Name) + "\n")
+	if record.Error != "" {
+		b.WriteString(styles.Error.Render(record.Error) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(styles.Subtle.Render("File: ") + collapseHome(record.Path) + "\n")
+	b.WriteString(styles.Subtle.Render("Root: ") + collapseHome(record.Root) + "\n")
+	session := record.Session
+	if record.State != stateNone {
+		session += " (" + record.State + ")"
+	}
+	b.WriteString(styles.Subtle.Render("Session: ") + session + "\n")
+	if len(record.Tags) > 0 {
+		b.WriteString(styles.Subtle.Render("Tags: ") + strings.Join(record.Tags, ", ") + "\n")
+	}
+
+	if len(record.Tabs) > 0 {
+		b.WriteString("\n" + styles.Subtle.Render("Tabs:") + "\n")
+		for _, tab := range record.Tabs {
+			b.WriteString("  • " + tab + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formTheme returns a huh theme that matches our styling
+func formTheme() *huh.Theme {
+	theme := huh.ThemeCharm()
+	theme.Focused.Base = theme.Focused.Base.BorderForeground(styles.Info.GetForeground())
+	theme.Focused.Title = styles.Title
+	return theme
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,30 +13,49 @@ import (
 	"github.com/dphaener/zellijinator/internal/styles"
 	"github.com/dphaener/zellijinator/internal/zellij"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var startCmd = &cobra.Command{
-	Use:   "start [project]",
+	Use:   "start [project] [args...] [key=value...]",
 	Short: "Start a zellijinator project",
-	Long:  `Start a Zellij session using the specified project configuration`,
-	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		var projectName string
-		
-		if len(args) == 0 {
-			// No project specified, show interactive selection
-			selected, err := selectProject("Select a project to start:")
-			if err != nil {
-				fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error: %v", err)))
-				os.Exit(1)
+	Long: `Start a Zellij session using the specified project configuration.
+
+With no project, the nearest .zellijinator.yml (or .zellijinator.yaml) in the
+current directory or any parent is started. Use "." to require a local project.
+Local project files must be approved before their commands are run.
+
+Arguments after the project name are made available to the project file.
+A file containing the line "# zellijinator: template" is rendered as a Go
+text/template before it is parsed:
+
+  zellijinator start myproj 1234 branch=feature-x
+
+  # zellijinator: template
+  root: ~/src/myproj-{{ var "branch" | default "main" }}
+  tabs:
+    - name: {{ arg 0 }}
+      panes:
+        - commands: ["git checkout {{ required "branch" }}"]
+
+Positional arguments are read with "arg N" (or .Args) and key=value
+arguments with "var", "required" (or .Settings). Other functions: env, default,
+cwd, cwd_basename, basename, dirname, hostname, user, git_branch, lower, upper.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, vars := splitProjectArgs(args)
+
+		if projectName == "" {
+			if hasLocalProject() {
+				// A project file in this repository takes precedence
+				projectName = "."
+			} else {
+				// No project specified, show interactive selection
+				return startFromPicker(cmd, vars)
 			}
-			projectName = selected
-		} else {
-			projectName = args[0]
 		}
-		
-		StartProject(projectName)
+
+		return StartProject(cmd, projectName, vars)
 	},
 }
 
@@ -44,126 +64,188 @@ func init() {
 }
 
 // StartProject starts a Zellij session for the given project
-// Exported so it can be used by root command
-func StartProject(name string) {
-	// Load project configuration
-	projectPath := config.ProjectPath(name)
-	data, err := os.ReadFile(projectPath)
+// Exported so it can be used by root command. The name "." starts the
+// project defined by a .zellijinator.yml in the current directory or one
+// of its parents. vars are passed to the project file's template.
+func StartProject(cmd *cobra.Command, name string, vars config.Vars) error {
+	if group, ok := strings.CutPrefix(name, "@"); ok {
+		return startGroup(cmd, group, vars)
+	}
+
+	projectPath, err := config.Find(name)
 	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Project %s not found. Create it with: %s", styles.Bold.Render(name), styles.Command.Render(fmt.Sprintf("zellijinator new %s", name)))))
-		} else {
-			fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error reading project file: %v", err)))
+		return err
+	}
+	return startProjectFile(cmd, name, projectPath, vars, flagStartOptions())
+}
+
+// startOptions choose where and as what a project is started
+type startOptions struct {
+	worktree       string
+	createWorktree bool
+	newInstance    bool
+	instance       string
+}
+
+// flagStartOptions returns the start options given on the command line
+func flagStartOptions() startOptions {
+	return startOptions{
+		worktree:       startWorktree,
+		createWorktree: createWorktree,
+		newInstance:    newInstance,
+		instance:       instanceSuffix,
+	}
+}
+
+// startProjectFile loads and starts the project file at path. The start
+// is recorded in the history as Zellij is launched, so a session that runs
+// for hours is recorded when it starts; failures before then are recorded
+// as failed.
+func startProjectFile(cmd *cobra.Command, name string, path string, vars config.Vars, opts startOptions) error {
+	local := config.IsLocalProject(path)
+
+	entry := config.HistoryEntry{
+		Project: name,
+		Path:    path,
+		Local:   local,
+		Time:    time.Now(),
+		Args:    vars.Args,
+		Vars:    vars.Settings,
+	}
+
+	project, err := config.LoadFile(path, vars)
+	if err == nil && local {
+		// Local project files come from the repository and must be
+		// approved, as they were just read, before anything is run
+		if err = ensureTrusted(cmd, project); err != nil {
+			return err
 		}
-		os.Exit(1)
+	}
+	if err == nil {
+		err = checkSessionCollision(project)
+	}
+	if err == nil {
+		entry.Worktree, err = applyWorktree(cmd, project, opts)
+	}
+	if err == nil {
+		entry.Instance, err = applyInstance(project, opts)
 	}
 
-	// Parse YAML
-	var project config.Project
-	if err := yaml.Unmarshal(data, &project); err != nil {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error parsing project file: %v", err)))
-		os.Exit(1)
+	recorded := false
+	if err == nil {
+		if local {
+			entry.Project = project.Name
+		}
+		err = startProjectSession(cmd, project, func(outcome string) {
+			entry.Outcome = outcome
+			recordHistory(cmd, entry, nil)
+			recorded = true
+		})
 	}
 
-	// Expand home directory in root path
-	if project.Root != "" && strings.HasPrefix(project.Root, "~/") {
-		home, _ := os.UserHomeDir()
-		project.Root = filepath.Join(home, project.Root[2:])
+	// Once Zellij has been launched its exit status says nothing about
+	// whether the start worked, so only earlier failures are recorded
+	if !recorded && err != nil {
+		recordHistory(cmd, entry, err)
 	}
-	
-	// Expand environment variables in root path
-	project.Root = os.ExpandEnv(project.Root)
+	return err
+}
 
+// recordHistory appends a start attempt to the history, marking it failed
+// when err is set. Cancelled starts aren't recorded.
+func recordHistory(cmd *cobra.Command, entry config.HistoryEntry, err error) {
+	if errors.Is(err, ErrCancelled) {
+		return
+	}
+	if err != nil {
+		entry.Outcome = config.OutcomeFailed
+		entry.Error = err.Error()
+	}
+	if histErr := config.AppendHistory(entry); histErr != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Failed to record history: %v", histErr)))
+	}
+}
+
+// checkSessionCollision fails when another project file uses the same
+// session name, since starting this one would attach to that one's session
+func checkSessionCollision(project *config.Project) error {
+	if collision := config.FindSessionCollision(project); collision != nil {
+		return collision
+	}
+	return nil
+}
+
+// splitProjectArgs separates the project name from the template arguments
+// that follow it. The name is empty when the first argument is already a
+// key=value setting, so the local project or picker is used instead.
+func splitProjectArgs(args []string) (string, config.Vars) {
+	if len(args) == 0 || config.IsVarArg(args[0]) {
+		return "", config.ParseVars(args)
+	}
+	return args[0], config.ParseVars(args[1:])
+}
+
+// hasLocalProject reports whether the current directory or one of its
+// parents contains a local project file
+func hasLocalProject() bool {
+	path, err := config.FindLocalProject("")
+	return err == nil && path != ""
+}
+
+// startProjectSession creates or attaches to the Zellij session for a
+// loaded project. launched is called with config.OutcomeStarted or
+// config.OutcomeAttached just before Zellij takes over the terminal, or
+// once a detached session is running.
+func startProjectSession(cmd *cobra.Command, project *config.Project, launched func(outcome string)) error {
 	// Use project name if session name not specified
-	sessionName := project.SessionName
-	if sessionName == "" {
-		sessionName = project.Name
+	sessionName := project.SessionNameOrDefault()
+	if err := config.CheckSessionName(sessionName); err != nil {
+		return err
+	}
+
+	// Background sessions don't use this terminal, so they can be
+	// created from inside Zellij too
+	if startDetached {
+		return startSessionDetached(cmd, project, sessionName, launched)
 	}
 
 	// Check if we're already in a Zellij session
 	if os.Getenv("ZELLIJ") != "" {
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg("Already inside a Zellij session."))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg("Please exit the current session first (Ctrl+q) before starting a new one."))
-		os.Exit(1)
+		return ErrInsideZellij
 	}
 
-	// Check if session already exists (active or dead)
-	sessionActive := false
-	checkCmd := exec.Command("zellij", "list-sessions", "-n")
-	output, err := checkCmd.CombinedOutput()
-	
-	// If the command succeeds and we have output, check for existing session
-	if err == nil && len(output) > 0 {
-		sessions := string(output)
-		for _, line := range strings.Split(sessions, "\n") {
-			if strings.TrimSpace(line) == sessionName {
-				sessionActive = true
-				break
-			}
-		}
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return ErrZellijMissing
 	}
 
+	// Attach to a running session. An exited one takes the create path
+	// below, whose fallback attach resurrects it.
+	existing := zellij.FindSession(sessionName)
+	sessionActive := existing != nil && !existing.Exited
+
 	// If session is active, attach to it
 	if sessionActive {
-		fmt.Println(styles.InfoMsg(fmt.Sprintf("Attaching to existing session %s...", styles.Bold.Render(sessionName))))
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Attaching to existing session %s...", styles.Bold.Render(sessionName))))
 		attachCmd := exec.Command("zellij", "attach", sessionName)
 		attachCmd.Stdin = os.Stdin
 		attachCmd.Stdout = os.Stdout
 		attachCmd.Stderr = os.Stderr
 		
+		launched(config.OutcomeAttached)
 		if err := attachCmd.Run(); err != nil {
-			fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error attaching to session: %v", err)))
-			os.Exit(1)
+			return &SessionError{Session: sessionName, Err: fmt.Errorf("attaching: %w", err)}
 		}
-		return
+		return nil
 	}
 	
 	// Session is not in active list. Try to create it - if it exists but is dead,
 	// Zellij will tell us and we'll handle it
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Creating new session %s...", styles.Bold.Render(sessionName))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Creating new session %s...", styles.Bold.Render(sessionName))))
 
 	// Generate layout or use custom layout file
-	var layoutPath string
-	if project.Layout != "" {
-		// Expand home directory in layout path
-		if strings.HasPrefix(project.Layout, "~/") {
-			home, _ := os.UserHomeDir()
-			project.Layout = filepath.Join(home, project.Layout[2:])
-		}
-		layoutPath = project.Layout
-	} else {
-		// Generate layout from config
-		layout := zellij.GenerateLayout(&project)
-		
-		// Create temporary layout file in a more persistent location
-		tmpDir := filepath.Join(os.TempDir(), "zellijinator")
-		os.MkdirAll(tmpDir, 0755)
-		
-		tmpFile, err := os.CreateTemp(tmpDir, fmt.Sprintf("%s-*.kdl", sessionName))
-		if err != nil {
-			fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error creating temp layout: %v", err)))
-			os.Exit(1)
-		}
-		// Don't remove the file immediately - Zellij needs it!
-		// We'll clean up old files on next run
-		
-		if _, err := tmpFile.WriteString(layout); err != nil {
-			fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error writing layout: %v", err)))
-			os.Exit(1)
-		}
-		tmpFile.Close()
-		
-		layoutPath = tmpFile.Name()
-		
-		// Clean up old layout files (older than 24 hours)
-		cleanupOldLayouts(tmpDir)
-		
-		// Debug: print layout if ZELLIJINATOR_DEBUG is set
-		if os.Getenv("ZELLIJINATOR_DEBUG") != "" {
-			fmt.Println(styles.InfoMsg(fmt.Sprintf("Generated layout file: %s", styles.Path.Render(layoutPath))))
-			fmt.Println(styles.InfoMsg("Layout content:"))
-			fmt.Println(layout)
-		}
+	layoutPath, err := writeLayout(cmd, project, sessionName)
+	if err != nil {
+		return err
 	}
 
 	// For new sessions, just use the layout
@@ -175,12 +257,10 @@ func StartProject(name string) {
 	startCmd.Stdin = os.Stdin
 	startCmd.Stdout = os.Stdout  
 	startCmd.Stderr = os.Stderr
-	startCmd.Dir = project.Root
-	startCmd.Env = os.Environ()
-	for k, v := range project.Env {
-		startCmd.Env = append(startCmd.Env, fmt.Sprintf("%s=%s", k, v))
-	}
+	startCmd.Dir = project.ResolvedRoot()
+	startCmd.Env = projectEnv(project)
 	
+	launched(config.OutcomeStarted)
 	if err := startCmd.Run(); err != nil {
 		// If it failed, it might be because the session already exists
 		// Let's check by trying to attach
@@ -192,19 +272,84 @@ func StartProject(name string) {
 		attachErr := attachCmd.Run()
 		if attachErr == nil {
 			// Successfully attached to existing session
-			return
+			return nil
 		}
 		
 		// Both creating and attaching failed
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Error starting Zellij session: %v", err)))
-		fmt.Fprintln(os.Stderr, styles.ErrorMsg(fmt.Sprintf("Also failed to attach to session %s: %v", styles.Bold.Render(sessionName), attachErr)))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg(fmt.Sprintf("Command was: %s", styles.Command.Render(fmt.Sprintf("zellij %s", strings.Join(args, " "))))))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg("\nDebug info:"))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg(fmt.Sprintf("- Layout file: %s", styles.Path.Render(layoutPath))))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg(fmt.Sprintf("- Session name: %s", styles.Bold.Render(sessionName))))
-		fmt.Fprintln(os.Stderr, styles.InfoMsg(fmt.Sprintf("- Working directory: %s", styles.Path.Render(project.Root))))
-		os.Exit(1)
+		return &SessionError{
+			Session: sessionName,
+			Err:     fmt.Errorf("starting: %v; attaching: %v", err, attachErr),
+			Details: []string{
+				fmt.Sprintf("Command was: %s", styles.Command.Render(fmt.Sprintf("zellij %s", strings.Join(args, " ")))),
+				"\nDebug info:",
+				fmt.Sprintf("- Layout file: %s", styles.Path.Render(layoutPath)),
+				fmt.Sprintf("- Session name: %s", styles.Bold.Render(sessionName)),
+				fmt.Sprintf("- Working directory: %s", styles.Path.Render(project.ResolvedRoot())),
+			},
+		}
+	}
+
+	return nil
+}
+
+// projectEnv is the environment Zellij is started with: ours plus the
+// project's env
+func projectEnv(project *config.Project) []string {
+	env := os.Environ()
+	for k, v := range project.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
+// writeLayout returns the layout file to start project with: a copy of
+// its custom layout as it was loaded, so what was trusted is what runs, or
+// one generated from its tabs
+func writeLayout(cmd *cobra.Command, project *config.Project, sessionName string) (string, error) {
+	var layoutPath string
+	source := project.LayoutSource()
+	if project.Layout != "" && source == nil {
+		// Expand home directory in layout path
+		layoutPath = config.ExpandPath(project.Layout)
+	} else {
+		// Generate layout from config, or copy the loaded one
+		var layout string
+		if source != nil {
+			layout = string(source.Data)
+		} else {
+			layout = zellij.GenerateLayout(project)
+		}
+		
+		// Create temporary layout file in a more persistent location
+		tmpDir := filepath.Join(os.TempDir(), "zellijinator")
+		os.MkdirAll(tmpDir, 0755)
+		
+		tmpFile, err := os.CreateTemp(tmpDir, fmt.Sprintf("%s-*.kdl", sessionName))
+		if err != nil {
+			return "", fmt.Errorf("creating temp layout: %w", err)
+		}
+		// Don't remove the file immediately - Zellij needs it!
+		// We'll clean up old files on next run
+		
+		if _, err := tmpFile.WriteString(layout); err != nil {
+			tmpFile.Close()
+			return "", fmt.Errorf("writing layout: %w", err)
+		}
+		tmpFile.Close()
+		
+		layoutPath = tmpFile.Name()
+		
+		// Clean up old layout files (older than 24 hours)
+		cleanupOldLayouts(tmpDir)
+		
+		// Debug: print layout if ZELLIJINATOR_DEBUG is set
+		if os.Getenv("ZELLIJINATOR_DEBUG") != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Generated layout file: %s", styles.Path.Render(layoutPath))))
+			fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Layout content:"))
+			fmt.Fprintln(cmd.OutOrStdout(), layout)
+		}
 	}
+	return layoutPath, nil
 }
 
 // cleanupOldLayouts removes layout files older than 24 hours
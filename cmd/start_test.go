@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dphaener/zellijinator/config"
+)
+
+// fakeZellij puts a zellij on PATH that lists sessions, exits with
+// status 0 for kill-session and 1 for everything else. Its arguments are
+// logged to $HOME/zellij.log.
+func fakeZellij(t *testing.T, sessions string) {
+	t.Helper()
+	script := "#!/bin/sh\necho \"$@\" >> \"$HOME/zellij.log\"\n" +
+		"if [ \"$1\" = list-sessions ]; then printf '" + sessions + "'; exit 0; fi\n" +
+		"if [ \"$1\" = kill-session ]; then exit 0; fi\nexit 1\n"
+	path := filepath.Join(os.Getenv("HOME"), "bin", "zellij")
+	writeFile(t, path, script)
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T)
+		code    int
+		outcome string
+	}{
+		{
+			name:    "created",
+			setup:   func(t *testing.T) { fakeZellij(t, "") },
+			code:    ExitSessionFailed,
+			outcome: config.OutcomeStarted,
+		},
+		{
+			name:    "attached",
+			setup:   func(t *testing.T) { fakeZellij(t, "api [Created 1m ago]\\n") },
+			code:    ExitSessionFailed,
+			outcome: config.OutcomeAttached,
+		},
+		{
+			name:    "failed before launch",
+			setup:   func(t *testing.T) { t.Setenv("ZELLIJ", "0") },
+			code:    ExitSessionFailed,
+			outcome: config.OutcomeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir := testEnv(t)
+			writeFile(t, filepath.Join(configDir, "api.yaml"), testProject)
+			tt.setup(t)
+
+			code, stdout, stderr := run(t, "", "start", "api")
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.code, stdout, stderr)
+			}
+
+			entries, err := config.ReadHistory()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("history has %d entries, want 1: %+v", len(entries), entries)
+			}
+			if entries[0].Outcome != tt.outcome {
+				t.Errorf("outcome = %q, want %q", entries[0].Outcome, tt.outcome)
+			}
+		})
+	}
+}
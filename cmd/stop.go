@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"github.com/spf13/cobra"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop <session|project|@group>",
+	Short: "Stop a running Zellij session",
+	Long: `Kill a running Zellij session. The argument is a session name, a
+project whose session should be stopped, or @group to stop every project in
+a group. With --all, the project's instances (<session>-2, ...) and
+worktree sessions are stopped too.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSessions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if group, ok := strings.CutPrefix(args[0], "@"); ok {
+			return stopGroup(cmd, group)
+		}
+		if stopAll {
+			return stopProjectSessions(cmd, args[0])
+		}
+		return stopSession(cmd, args[0])
+	},
+}
+
+var stopAll bool
+
+func init() {
+	stopCmd.Flags().BoolVarP(&stopAll, "all", "a", false, "Also stop the project's instances and worktree sessions")
+	rootCmd.AddCommand(stopCmd)
+}
+
+func stopSession(cmd *cobra.Command, name string) error {
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return ErrZellijMissing
+	}
+
+	sessionName := resolveSessionName(name)
+	session := zellij.FindSession(sessionName)
+	if session == nil || session.Exited {
+		if record := findProjectRecord(name); record != nil && len(runningChildren(record)) > 0 {
+			return fmt.Errorf("no running Zellij session named '%s', but %s has other sessions running (use --all to stop them)", sessionName, name)
+		}
+		return fmt.Errorf("no running Zellij session named '%s'", sessionName)
+	}
+	return stopNamedSession(cmd, sessionName)
+}
+
+// stopProjectSessions stops a project's session along with its instances
+// and worktree sessions
+func stopProjectSessions(cmd *cobra.Command, name string) error {
+	if _, err := exec.LookPath("zellij"); err != nil {
+		return ErrZellijMissing
+	}
+
+	record := findProjectRecord(name)
+	if record == nil {
+		return &config.NotFoundError{Name: name}
+	}
+
+	sessions := runningChildren(record)
+	if record.State == stateActive {
+		sessions = append([]string{record.Session}, sessions...)
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no running Zellij sessions for %s", name)
+	}
+
+	for _, sessionName := range sessions {
+		if err := stopNamedSession(cmd, sessionName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findProjectRecord returns the project started with name, or nil
+func findProjectRecord(name string) *projectRecord {
+	records, err := collectProjects()
+	if err != nil {
+		return nil
+	}
+	for _, record := range records {
+		if pickerName(record) == name {
+			return &record
+		}
+	}
+	return nil
+}
+
+// runningChildren returns the running instance and worktree sessions of a
+// project
+func runningChildren(record *projectRecord) []string {
+	var names []string
+	for _, child := range append(record.Instances, record.Worktrees...) {
+		if child.State == stateActive {
+			names = append(names, child.Session)
+		}
+	}
+	return names
+}
+
+func stopNamedSession(cmd *cobra.Command, sessionName string) error {
+	if output, err := exec.Command("zellij", "kill-session", sessionName).CombinedOutput(); err != nil {
+		sessionErr := &SessionError{Session: sessionName, Err: fmt.Errorf("stopping: %w", err)}
+		if details := strings.TrimSpace(string(output)); details != "" {
+			sessionErr.Details = append(sessionErr.Details, details)
+		}
+		return sessionErr
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Stopped session %s", styles.Bold.Render(sessionName))))
+	return nil
+}
+
+// resolveSessionName returns name if it is a Zellij session, otherwise
+// the session name of the project called name
+func resolveSessionName(name string) string {
+	if zellij.FindSession(name) != nil {
+		return name
+	}
+
+	path, err := config.Find(name)
+	if err != nil {
+		return name
+	}
+	project, err := config.LoadFile(path, config.Vars{Partial: true})
+	if err != nil {
+		return name
+	}
+	return project.SessionNameOrDefault()
+}
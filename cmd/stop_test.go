@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+)
+
+func TestStopAllOnlyStopsOwnSessions(t *testing.T) {
+	configDir := testEnv(t)
+	apiPath := filepath.Join(configDir, "api.yaml")
+	writeFile(t, apiPath, testProject)
+	writeFile(t, filepath.Join(configDir, "api-gateway.yaml"), strings.Replace(testProject, "name: api", "name: api-gateway", 1))
+	if err := config.AppendHistory(config.HistoryEntry{Project: "api", Path: apiPath, Time: time.Now(), Instance: "review", Outcome: config.OutcomeStarted}); err != nil {
+		t.Fatal(err)
+	}
+	fakeZellij(t, strings.Join([]string{
+		"api", "api-2", "api-review", "api-scratch", "api@feat",
+		"api-gateway", "api-gateway-2", "api-gateway@feat",
+	}, "\\n")+"\\n")
+
+	code, stdout, stderr := run(t, "", "stop", "api", "--all")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	log, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), "zellij.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stopped []string
+	for _, line := range strings.Split(string(log), "\n") {
+		if name, ok := strings.CutPrefix(line, "kill-session "); ok {
+			stopped = append(stopped, name)
+		}
+	}
+	want := []string{"api", "api-2", "api-review", "api@feat"}
+	if strings.Join(stopped, " ") != strings.Join(want, " ") {
+		t.Errorf("stopped %q, want %q", stopped, want)
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var templateFrom string
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage project templates",
+	Long: `Manage the templates new projects can be created from with
+'zellijinator new <project> --template <name>'.
+
+User templates live in the templates/ directory of the config directory and
+take precedence over the built-in templates with the same name.`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available project templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listTemplates(cmd)
+	},
+}
+
+var templatesNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a user template",
+	Long: `Create a user template and open it in your editor.
+
+The template starts as a copy of the built-in minimal template, or of an
+existing project with --from, with its name and root replaced by the
+[[ .Name ]] and [[ .Root ]] placeholders.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createTemplate(cmd, args[0], templateFrom)
+	},
+}
+
+func init() {
+	templatesNewCmd.Flags().StringVar(&templateFrom, "from", "", "Start the template from an existing project")
+	templatesNewCmd.RegisterFlagCompletionFunc("from", completeProjectNames)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesNewCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
+
+func listTemplates(cmd *cobra.Command) error {
+	templates, err := config.ListTemplates()
+	if err != nil {
+		return fmt.Errorf("listing templates: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.Title.Render("Project templates"))
+	for _, tmpl := range templates {
+		line := "  " + styles.Bold.Render(tmpl.Name)
+		if tmpl.Description != "" {
+			line += "  " + tmpl.Description
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+
+		switch {
+		case tmpl.Builtin:
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("    built-in"))
+		case tmpl.Overrides:
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("    "+tmpl.Path+" (overrides built-in)"))
+		default:
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("    "+tmpl.Path))
+		}
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("Create a project from one with: zellijinator new <project> --template <name>"))
+	return nil
+}
+
+func createTemplate(cmd *cobra.Command, name string, from string) error {
+	path := filepath.Join(config.TemplateDirs()[0], name+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("template '%s' already exists at %s", name, path)
+	}
+
+	var data []byte
+	if from != "" {
+		projectPath, err := config.Find(from)
+		if err != nil {
+			return err
+		}
+		data, err = templateFromProject(projectPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		_, data, err = config.ReadTemplate("minimal")
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("creating template file: %w", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created template %s", styles.Bold.Render(name))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Template file: "+styles.Path.Render(path)))
+
+	openInEditor(cmd, path)
+	return nil
+}
+
+// templatePlaceholders stand in for the name and root actions while the
+// template is encoded, since the encoder would quote a value starting with [
+var templatePlaceholders = map[string]string{
+	"name": "ZELLIJINATOR_TEMPLATE_NAME",
+	"root": "ZELLIJINATOR_TEMPLATE_ROOT",
+}
+
+// templateFromProject turns a project file into a template by replacing
+// its name and root with placeholders. Comments are kept.
+func templateFromProject(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a project file", path)
+	}
+
+	mapping := doc.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		value := mapping.Content[i+1]
+		if placeholder, ok := templatePlaceholders[mapping.Content[i].Value]; ok {
+			value.Value, value.Style, value.Tag = placeholder, 0, "!!str"
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	data = bytes.Replace(out.Bytes(), []byte(templatePlaceholders["name"]), []byte("[[ .Name | quote ]]"), 1)
+	data = bytes.Replace(data, []byte(templatePlaceholders["root"]), []byte("[[ .Root | quote ]]"), 1)
+	return data, nil
+}
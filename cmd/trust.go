@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+// ensureTrusted asks the user to approve a loaded repository-local project
+// before any of its commands are run. Approval is recorded against the
+// hash of the file and the files it extends or includes, so the prompt
+// comes back whenever one of them changes.
+func ensureTrusted(cmd *cobra.Command, project *config.Project) error {
+	path := project.Path
+	trusted, err := config.IsTrusted(project)
+	if err != nil {
+		return fmt.Errorf("checking trust for %s: %w", path, err)
+	}
+	if trusted {
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.WarningMsg(fmt.Sprintf("%s has not been approved yet (or has changed since it was).", styles.Path.Render(path))))
+	if len(project.Sources) > 1 {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("It also reads:"))
+		for _, source := range project.Sources[1:] {
+			fmt.Fprintln(cmd.OutOrStdout(), styles.Subtle.Render("  - "+collapseHome(source.Path)))
+		}
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Starting it will run the commands it defines. Review the files before continuing."))
+	fmt.Fprint(cmd.OutOrStdout(), styles.Prompt.Render("Trust this file and start the project? (y/N): "))
+
+	reader := bufio.NewReader(cmd.InOrStdin())
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	if response != "y" && response != "yes" {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("Start cancelled."))
+		return ErrCancelled
+	}
+
+	if err := config.Trust(project); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), styles.WarningMsg(fmt.Sprintf("Failed to record trust: %v", err)))
+	}
+	return nil
+}
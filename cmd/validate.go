@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [project...]",
+	Short: "Check project files for problems",
+	Long: `Check project files for problems without starting them: YAML and template
+errors, invalid panes, session names Zellij can't use (illegal characters or
+too long for the socket directory), and projects that share a session name.
+
+Without arguments every project is checked, including the local one.`,
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return validateProjects(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func validateProjects(cmd *cobra.Command, names []string) error {
+	records, err := collectProjects()
+	if err != nil {
+		return err
+	}
+
+	if len(names) > 0 {
+		byName := make(map[string]projectRecord)
+		for _, record := range records {
+			byName[pickerName(record)] = record
+		}
+		var selected []projectRecord
+		for _, name := range names {
+			record, ok := byName[name]
+			if !ok {
+				return &config.NotFoundError{Name: name}
+			}
+			selected = append(selected, record)
+		}
+		records = selected
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg("No projects found."))
+		return nil
+	}
+
+	collisions, err := config.SessionCollisions()
+	if err != nil {
+		return err
+	}
+	collisionsByPath := make(map[string]*config.SessionCollisionError)
+	for _, collision := range collisions {
+		for _, path := range collision.Paths {
+			collisionsByPath[path] = collision
+		}
+	}
+
+	failed := 0
+	for _, record := range records {
+		problems := projectProblems(record, collisionsByPath[record.Path])
+		if len(problems) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", styles.Success.Render("✓"), styles.Bold.Render(pickerName(record)))
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s\n", styles.Error.Render("✗"), styles.Bold.Render(pickerName(record)), styles.Path.Render(collapseHome(record.Path)))
+		for _, problem := range problems {
+			fmt.Fprintf(cmd.OutOrStdout(), "    - %s\n", problem)
+		}
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout())
+	if failed > 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), styles.ErrorMsg(fmt.Sprintf("%d of %d projects have problems.", failed, len(records))))
+		return ErrValidationFailed
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("All %d projects are valid.", len(records))))
+	return nil
+}
+
+// projectProblems lists what is wrong with a project, with the fix where
+// there is an obvious one
+func projectProblems(record projectRecord, collision *config.SessionCollisionError) []string {
+	project, err := config.LoadFile(record.Path, config.Vars{Partial: true})
+	if err != nil {
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationErr.Problems
+		}
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	session := project.SessionNameOrDefault()
+	var nameErr *config.SessionNameError
+	if err := config.CheckSessionName(session); errors.As(err, &nameErr) {
+		problem := fmt.Sprintf("session name %q %s", nameErr.Name, nameErr.Problem)
+		if nameErr.Suggestion != "" {
+			problem += fmt.Sprintf("; set session_name: %s", nameErr.Suggestion)
+		}
+		problems = append(problems, problem)
+	}
+
+	if collision != nil {
+		for _, path := range collision.Paths {
+			if path != record.Path {
+				problems = append(problems, fmt.Sprintf("session name %q is also used by %s; set a different session_name", session, collapseHome(path)))
+			}
+		}
+	}
+	return problems
+}
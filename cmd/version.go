@@ -20,7 +20,7 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version information",
 	Long:  `Display detailed version information about zellijinator`,
 	Run: func(cmd *cobra.Command, args []string) {
-		showVersion()
+		showVersion(cmd)
 	},
 }
 
@@ -28,12 +28,12 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 }
 
-func showVersion() {
-	fmt.Println(styles.Title.Render("Zellijinator"))
-	fmt.Println()
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Version:   %s", styles.Bold.Render(version))))
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Commit:    %s", commit)))
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Built:     %s", date)))
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Go:        %s", runtime.Version())))
-	fmt.Println(styles.InfoMsg(fmt.Sprintf("Platform:  %s/%s", runtime.GOOS, runtime.GOARCH)))
+func showVersion(cmd *cobra.Command) {
+	fmt.Fprintln(cmd.OutOrStdout(), styles.Title.Render("Zellijinator"))
+	fmt.Fprintln(cmd.OutOrStdout())
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Version:   %s", styles.Bold.Render(version))))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Commit:    %s", commit)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Built:     %s", date)))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Go:        %s", runtime.Version())))
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Platform:  %s/%s", runtime.GOOS, runtime.GOARCH)))
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/ready"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waitProbe     config.ReadyProbe
+	waitLogFile   string
+	waitLogMatch  string
+	waitLogOffset int64
+)
+
+// waitReadyCmd is run by the generated layout in panes with depends_on,
+// once for each dependency that has a ready probe
+var waitReadyCmd = &cobra.Command{
+	Use:    "wait-ready <pane>",
+	Short:  "Wait for a pane's ready probe to pass",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if waitLogFile != "" || waitLogMatch != "" {
+			waitProbe.Log = &config.LogProbe{File: waitLogFile, Match: waitLogMatch, Offset: waitLogOffset}
+		}
+		return waitForPane(cmd, args[0], waitProbe)
+	},
+}
+
+func init() {
+	waitReadyCmd.Flags().StringVar(&waitProbe.Port, "port", "", "TCP port or host:port that must accept connections")
+	waitReadyCmd.Flags().StringVar(&waitProbe.File, "file", "", "File that must exist")
+	waitReadyCmd.Flags().StringVar(&waitProbe.Command, "command", "", "Command that must exit 0")
+	waitReadyCmd.Flags().StringVar(&waitLogFile, "log-file", "", "Log file to watch")
+	waitReadyCmd.Flags().StringVar(&waitLogMatch, "log-match", "", "Regular expression a log line must match")
+	waitReadyCmd.Flags().Int64Var(&waitLogOffset, "log-offset", -1, "Where in the log file to start matching (default: its current end)")
+	waitReadyCmd.Flags().StringVar(&waitProbe.Timeout, "timeout", "", "How long to wait (default 60s)")
+	rootCmd.AddCommand(waitReadyCmd)
+}
+
+func waitForPane(cmd *cobra.Command, name string, probe config.ReadyProbe) error {
+	fmt.Fprintln(cmd.OutOrStdout(), styles.InfoMsg(fmt.Sprintf("Waiting for %s (%s)...", styles.Bold.Render(name), probe.Describe())))
+
+	start := time.Now()
+	if err := ready.Wait(probe); err != nil {
+		if errors.Is(err, ready.ErrTimeout) {
+			return fmt.Errorf("%s was not ready after %s", name, probe.TimeoutDuration())
+		}
+		return fmt.Errorf("waiting for %s: %w", name, err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("%s is ready (%s)", styles.Bold.Render(name), time.Since(start).Round(100*time.Millisecond))))
+	return nil
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/zellij"
+	"gopkg.in/yaml.v3"
+)
+
+// runProjectWizard walks the user through defining a project: its root,
+// session name and default layout, then each tab and its panes. A preview
+// of the generated layout is shown while panes are being added.
+func runProjectWizard(name string) (*config.Project, error) {
+	cwd, _ := os.Getwd()
+
+	project := &config.Project{Name: name, Root: cwd}
+	var sessionName string
+
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Project root").
+				Description("enter selects a directory, l/→ opens it").
+				CurrentDirectory(cwd).
+				DirAllowed(true).
+				FileAllowed(false).
+				Height(10).
+				Value(&project.Root),
+			huh.NewInput().
+				Title("Session name").
+				Description("Leave empty to use the project name").
+				Placeholder(name).
+				Value(&sessionName),
+			huh.NewSelect[string]().
+				Title("Default layout").
+				Options(
+					huh.NewOption("standard (tab bar and status bar)", ""),
+					huh.NewOption("compact", "compact"),
+				).
+				Value(&project.DefaultLayout),
+		),
+	).WithTheme(formTheme()).Run()
+	if err != nil {
+		return nil, formError("wizard", err)
+	}
+	project.SessionName = strings.TrimSpace(sessionName)
+	project.Root = collapseHome(project.Root)
+
+	for {
+		tab, err := askTab(project)
+		if err != nil {
+			return nil, err
+		}
+		project.Tabs = append(project.Tabs, *tab)
+
+		addTab := false
+		err = huh.NewForm(
+			huh.NewGroup(
+				huh.NewConfirm().
+					Title("Add another tab?").
+					Value(&addTab),
+			),
+		).WithTheme(formTheme()).Run()
+		if err != nil {
+			return nil, formError("wizard", err)
+		}
+		if !addTab {
+			break
+		}
+	}
+
+	return project, nil
+}
+
+// askTab asks for a tab's name, layout and focus, then for its panes
+func askTab(project *config.Project) (*config.Tab, error) {
+	tab := &config.Tab{}
+
+	layoutOptions := []huh.Option[string]{huh.NewOption("manual splits", "")}
+	for _, preset := range zellij.LayoutPresets {
+		layoutOptions = append(layoutOptions, huh.NewOption(preset, preset))
+	}
+
+	fields := []huh.Field{
+		huh.NewInput().
+			Title(fmt.Sprintf("Tab %d name", len(project.Tabs)+1)).
+			Validate(func(value string) error {
+				value = strings.TrimSpace(value)
+				if value == "" {
+					return fmt.Errorf("a tab needs a name")
+				}
+				for _, existing := range project.Tabs {
+					if existing.Name == value {
+						return fmt.Errorf("there is already a tab named %s", value)
+					}
+				}
+				return nil
+			}).
+			Value(&tab.Name),
+		huh.NewSelect[string]().
+			Title("Pane layout").
+			Options(layoutOptions...).
+			Value(&tab.Layout),
+	}
+
+	// Only one tab should be focused when the session starts
+	hasFocusedTab := false
+	for _, existing := range project.Tabs {
+		hasFocusedTab = hasFocusedTab || existing.Focus
+	}
+	if !hasFocusedTab {
+		fields = append(fields, huh.NewConfirm().
+			Title("Focus this tab when the session starts?").
+			Value(&tab.Focus))
+	}
+
+	err := huh.NewForm(huh.NewGroup(fields...)).WithTheme(formTheme()).Run()
+	if err != nil {
+		return nil, formError("wizard", err)
+	}
+	tab.Name = strings.TrimSpace(tab.Name)
+
+	for {
+		addPane, err := askPane(project, tab)
+		if err != nil {
+			return nil, err
+		}
+		if !addPane {
+			break
+		}
+	}
+
+	return tab, nil
+}
+
+// askPane asks for one pane and appends it to tab. It reports whether the
+// user wants to add another pane.
+func askPane(project *config.Project, tab *config.Tab) (bool, error) {
+	pane := config.Pane{}
+	var commands string
+	var size string
+	addPane := false
+
+	fields := []huh.Field{
+		huh.NewText().
+			Title(fmt.Sprintf("Pane %d commands", len(tab.Panes)+1)).
+			Description("One command per line; leave empty for a plain shell").
+			Lines(4).
+			Value(&commands),
+	}
+
+	// With manual splits, every pane after the first splits from the previous one
+	if tab.Layout == "" && len(tab.Panes) > 0 {
+		pane.Split = "horizontal"
+		fields = append(fields,
+			huh.NewSelect[string]().
+				Title("Split").
+				Options(
+					huh.NewOption("horizontal (new pane below)", "horizontal"),
+					huh.NewOption("vertical (new pane to the right)", "vertical"),
+				).
+				Value(&pane.Split),
+			huh.NewInput().
+				Title("Size").
+				Description("Percentage of the space the new pane takes; leave empty for an even split").
+				Validate(func(value string) error {
+					if value == "" {
+						return nil
+					}
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 || n > 100 {
+						return fmt.Errorf("size must be a number from 1 to 100")
+					}
+					return nil
+				}).
+				Value(&size),
+		)
+	}
+
+	fields = append(fields,
+		huh.NewConfirm().
+			Title("Focus this pane?").
+			Value(&pane.Focus),
+		huh.NewNote().
+			Title("Preview").
+			DescriptionFunc(func() string {
+				preview := pane
+				preview.Commands = splitCommands(commands)
+				preview.Size = size
+				return previewTab(project, tab, preview)
+			}, []any{&commands, &size, &pane}),
+		huh.NewConfirm().
+			Title("Add another pane to this tab?").
+			Value(&addPane),
+	)
+
+	err := huh.NewForm(huh.NewGroup(fields...)).WithTheme(formTheme()).Run()
+	if err != nil {
+		return false, formError("wizard", err)
+	}
+
+	pane.Commands = splitCommands(commands)
+	pane.Size = size
+	tab.Panes = append(tab.Panes, pane)
+	return addPane, nil
+}
+
+// confirmProject shows the project that will be written and asks for
+// confirmation
+func confirmProject(project *config.Project, path string) (bool, error) {
+	data, err := yaml.Marshal(project)
+	if err != nil {
+		return false, err
+	}
+
+	confirmed := true
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(path).
+				Description(string(data)),
+			huh.NewConfirm().
+				Title("Write this project?").
+				Value(&confirmed),
+		),
+	).WithTheme(formTheme()).Run()
+	if err != nil {
+		return false, formError("wizard", err)
+	}
+	return confirmed, nil
+}
+
+// previewTab renders the KDL for tab with pending added as its next pane
+func previewTab(project *config.Project, tab *config.Tab, pending config.Pane) string {
+	previewTab := *tab
+	previewTab.Panes = append(append([]config.Pane{}, tab.Panes...), pending)
+	previewTab.Focus = true
+
+	preview := *project
+	preview.Tabs = []config.Tab{previewTab}
+	layout := zellij.GenerateLayout(&preview)
+
+	// Only show the tab itself, not the surrounding templates
+	lines := strings.Split(layout, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "tab name=") {
+			lines = lines[i:]
+			break
+		}
+	}
+	if len(lines) > 2 {
+		// Drop the closing brace of the layout block and the trailing newline
+		lines = lines[:len(lines)-2]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitCommands turns multi-line input into a list of commands
+func splitCommands(input string) []string {
+	var commands []string
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commands = append(commands, line)
+		}
+	}
+	return commands
+}
+
+// formError maps a huh form error: backing out of the form cancels what
+// prompted it, other failures (such as having no terminal) are returned
+func formError(what string, err error) error {
+	if errors.Is(err, huh.ErrUserAborted) {
+		return fmt.Errorf("%s %w", what, ErrCancelled)
+	}
+	return err
+}
+
+// collapseHome replaces the home directory prefix of path with ~ so the
+// written project is portable between machines
+func collapseHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"github.com/dphaener/zellijinator/internal/git"
+	"github.com/dphaener/zellijinator/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	startWorktree  string
+	createWorktree bool
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{rootCmd, startCmd} {
+		cmd.Flags().StringVarP(&startWorktree, "worktree", "w", "", "Start the project in the git worktree with this branch or directory name")
+		cmd.Flags().BoolVar(&createWorktree, "create-worktree", false, "Create the worktree if it doesn't exist, and the branch if needed")
+		cmd.RegisterFlagCompletionFunc("worktree", completeWorktrees)
+	}
+}
+
+// applyWorktree points project at a git worktree of its root: the one
+// named by --worktree, or for projects with worktrees: true the one
+// containing the current directory. The session is named
+// <session>@<branch>. It returns the worktree's name, empty when the
+// project is started in its root as usual.
+func applyWorktree(cmd *cobra.Command, project *config.Project, opts startOptions) (string, error) {
+	root := project.ResolvedRoot()
+
+	var wt *git.Worktree
+	if opts.worktree != "" {
+		found, err := git.FindWorktree(root, opts.worktree)
+		if err != nil {
+			return "", fmt.Errorf("finding worktree %s: %w", opts.worktree, err)
+		}
+		if found == nil {
+			if !opts.createWorktree {
+				return "", fmt.Errorf("no worktree of %s has %s checked out (use --create-worktree to add one)", root, opts.worktree)
+			}
+			path := worktreePath(project, root, opts.worktree)
+			if found, err = git.AddWorktree(root, path, opts.worktree); err != nil {
+				return "", err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), styles.SuccessMsg(fmt.Sprintf("Created worktree %s for %s", styles.Path.Render(path), styles.Bold.Render(opts.worktree))))
+		}
+		wt = found
+	} else if project.Worktrees {
+		wt = currentWorktree(root)
+	}
+
+	if wt == nil {
+		return "", nil
+	}
+
+	name := wt.Branch
+	if name == "" {
+		name = filepath.Base(wt.Path)
+	}
+	project.SessionName = project.SessionNameOrDefault() + "@" + strings.ReplaceAll(name, "/", "-")
+	project.Root = wt.Path
+	return name, nil
+}
+
+// currentWorktree returns the linked worktree of root's repository that
+// contains the current directory, or nil when the current directory is
+// elsewhere or in the main worktree
+func currentWorktree(root string) *git.Worktree {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	top, err := git.Toplevel(cwd)
+	if err != nil {
+		return nil
+	}
+	worktrees, err := git.ListWorktrees(root)
+	if err != nil {
+		return nil
+	}
+
+	for i, wt := range worktrees {
+		if i > 0 && samePath(wt.Path, top) {
+			return &wt
+		}
+	}
+	return nil
+}
+
+// worktreePath is where a new worktree for branch is created: in the
+// project's worktree_dir, or beside root as <root>-<branch>
+func worktreePath(project *config.Project, root string, branch string) string {
+	dirName := strings.ReplaceAll(branch, "/", "-")
+	if project.WorktreeDir != "" {
+		dir := config.ExpandPath(project.WorktreeDir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		return filepath.Join(dir, dirName)
+	}
+	return filepath.Join(filepath.Dir(root), filepath.Base(root)+"-"+dirName)
+}
+
+// samePath compares two paths after resolving symlinks
+func samePath(a string, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return a == b
+}
+
+// completeWorktrees completes the branches checked out in the worktrees
+// of the project being started
+func completeWorktrees(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	path, err := config.Find(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	project, err := config.LoadFile(path, config.Vars{Partial: true})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	worktrees, err := git.ListWorktrees(project.ResolvedRoot())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			completions = append(completions, wt.Branch+"\t"+collapseHome(wt.Path))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pane modes
+const (
+	// PaneModeShell runs the pane's commands in sh and then drops into the
+	// user's shell
+	PaneModeShell = "shell"
+	// PaneModeNative runs a single command as a Zellij command pane, which
+	// shows its exit status and can re-run it
+	PaneModeNative = "native"
+)
+
+// CommandLine is a command and its arguments. In YAML it is either a list
+// or a string, which is split like a shell would without running one.
+type CommandLine []string
+
+func (c *CommandLine) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		argv, err := SplitCommandLine(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: command %q: %w", value.Line, value.Value, err)
+		}
+		*c = argv
+		return nil
+	}
+	var argv []string
+	if err := value.Decode(&argv); err != nil {
+		return err
+	}
+	*c = argv
+	return nil
+}
+
+// SplitCommandLine splits a command into words, honouring single and
+// double quotes and backslash escapes. Pipes, redirects, variables and
+// other shell syntax are rejected, since no shell runs the command.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			case '$', '`':
+				return nil, fmt.Errorf("uses shell syntax %q; use mode: shell", r)
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune("|&;<>()$`*?", r):
+			return nil, fmt.Errorf("uses shell syntax %q; use mode: shell", r)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("has an unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("ends with a backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Native reports whether the pane runs as a Zellij command pane: when its
+// mode is native, or not set and it has a command
+func (p Pane) Native() bool {
+	return p.Mode == PaneModeNative || (p.Mode == "" && len(p.Command) > 0)
+}
+
+// Argv returns the command a native pane runs: its command, or its only
+// entry in commands split into words
+func (p Pane) Argv() []string {
+	if len(p.Command) > 0 {
+		return p.Command
+	}
+	if len(p.Commands) == 1 {
+		argv, _ := SplitCommandLine(p.Commands[0])
+		return argv
+	}
+	return nil
+}
+
+// ShellCommands returns the commands a shell pane runs, with command, if
+// set, quoted and run first
+func (p Pane) ShellCommands() []string {
+	if len(p.Command) == 0 {
+		return p.Commands
+	}
+	quoted := make([]string, len(p.Command))
+	for i, arg := range p.Command {
+		quoted[i] = ShellQuote(arg)
+	}
+	return append([]string{strings.Join(quoted, " ")}, p.Commands...)
+}
+
+// ShellQuote quotes s for use in a POSIX shell command
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// modeProblems returns what is wrong with the pane's mode and command
+func (p Pane) modeProblems() []string {
+	var problems []string
+
+	switch p.Mode {
+	case "", PaneModeShell, PaneModeNative:
+	default:
+		return []string{fmt.Sprintf("mode must be shell or native, got %q", p.Mode)}
+	}
+
+	if !p.Native() {
+		if p.CloseOnExit || p.StartSuspended {
+			problems = append(problems, "close_on_exit and start_suspended only apply to native command panes (mode: native)")
+		}
+		return problems
+	}
+
+	if p.Shell != "" || len(p.ShellArgs) > 0 || len(p.CommandShell) > 0 {
+		problems = append(problems, "shell, shell_args and command_shell don't apply to native command panes")
+	}
+
+	switch {
+	case len(p.Command) > 0 && len(p.Commands) > 0:
+		problems = append(problems, "a native pane runs one command; set command or commands, not both (or use mode: shell)")
+	case len(p.Command) == 0 && len(p.Commands) == 0:
+		problems = append(problems, "a native pane needs a command")
+	case len(p.Commands) > 1:
+		problems = append(problems, "a native pane runs one command; use mode: shell to run several")
+	case len(p.Commands) == 1:
+		if _, err := SplitCommandLine(p.Commands[0]); err != nil {
+			problems = append(problems, fmt.Sprintf("command %q %v", p.Commands[0], err))
+		} else if argv := p.Argv(); len(argv) == 0 {
+			problems = append(problems, "a native pane needs a command")
+		} else if argv[0] == "" {
+			problems = append(problems, "a native pane's command can't be empty")
+		}
+	case p.Command[0] == "":
+		problems = append(problems, "a native pane's command can't be empty")
+	}
+	return problems
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  string
+	}{
+		{line: "bin/server --port 3000", want: []string{"bin/server", "--port", "3000"}},
+		{line: "  tail\t-f  log/dev.log\n", want: []string{"tail", "-f", "log/dev.log"}},
+		{line: "", want: nil},
+		{line: `echo 'it''s' "a \"b\" \\ c"`, want: []string{"echo", "its", `a "b" \ c`}},
+		{line: `grep 'a|b' "x;y" 'not $HOME'`, want: []string{"grep", "a|b", "x;y", "not $HOME"}},
+		{line: `touch my\ file a\'b`, want: []string{"touch", "my file", "a'b"}},
+		{line: `printf '' ""`, want: []string{"printf", "", ""}},
+		{line: `""`, want: []string{""}},
+		{line: "make run | tee log", err: `uses shell syntax '|'; use mode: shell`},
+		{line: "cd web && npm start", err: `uses shell syntax '&'; use mode: shell`},
+		{line: "ls *.go", err: `uses shell syntax '*'; use mode: shell`},
+		{line: "echo $HOME", err: `uses shell syntax '$'; use mode: shell`},
+		{line: "echo \"$HOME\"", err: `uses shell syntax '$'; use mode: shell`},
+		{line: "echo \"`date`\"", err: "uses shell syntax '`'; use mode: shell"},
+		{line: `echo 'unterminated`, err: "has an unterminated ' quote"},
+		{line: `echo "unterminated`, err: `has an unterminated " quote`},
+		{line: `echo trailing\`, err: "ends with a backslash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := SplitCommandLine(tt.line)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("words = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneModeProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		pane     string
+		native   bool
+		problems []string
+	}{
+		{name: "shell", pane: "commands: [make setup, make run]"},
+		{name: "command list", pane: "command: [bin/server, --port, '3000']", native: true},
+		{name: "command string", pane: `command: bin/server --name "my app"`, native: true},
+		{name: "native commands entry", pane: "mode: native\ncommands: [bin/server]", native: true},
+		{name: "native options", pane: "command: top\nclose_on_exit: true\nstart_suspended: true", native: true},
+		{
+			name:     "unknown mode",
+			pane:     "mode: exec",
+			problems: []string{`mode must be shell or native, got "exec"`},
+		},
+		{
+			name:     "native options on a shell pane",
+			pane:     "commands: [top]\nclose_on_exit: true",
+			problems: []string{"close_on_exit and start_suspended only apply to native command panes (mode: native)"},
+		},
+		{
+			name:     "shell options on a native pane",
+			pane:     "command: top\nshell: fish",
+			native:   true,
+			problems: []string{"shell, shell_args and command_shell don't apply to native command panes"},
+		},
+		{
+			name:     "command and commands",
+			pane:     "command: top\ncommands: [htop]",
+			native:   true,
+			problems: []string{"a native pane runs one command; set command or commands, not both (or use mode: shell)"},
+		},
+		{
+			name:     "no command",
+			pane:     "mode: native",
+			native:   true,
+			problems: []string{"a native pane needs a command"},
+		},
+		{
+			name:     "blank commands entry",
+			pane:     "mode: native\ncommands: ['  ']",
+			native:   true,
+			problems: []string{"a native pane needs a command"},
+		},
+		{
+			name:     "several commands",
+			pane:     "mode: native\ncommands: [make setup, make run]",
+			native:   true,
+			problems: []string{"a native pane runs one command; use mode: shell to run several"},
+		},
+		{
+			name:     "shell syntax",
+			pane:     "mode: native\ncommands: [make run | tee log]",
+			native:   true,
+			problems: []string{`command "make run | tee log" uses shell syntax '|'; use mode: shell`},
+		},
+		{
+			name:     "empty command string",
+			pane:     `command: '""'`,
+			native:   true,
+			problems: []string{"a native pane's command can't be empty"},
+		},
+		{
+			name:     "empty command list entry",
+			pane:     "command: ['', --help]",
+			native:   true,
+			problems: []string{"a native pane's command can't be empty"},
+		},
+		{
+			name:     "empty commands entry",
+			pane:     "mode: native\ncommands: ['\"\" --help']",
+			native:   true,
+			problems: []string{"a native pane's command can't be empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pane Pane
+			if err := yaml.Unmarshal([]byte(tt.pane), &pane); err != nil {
+				t.Fatal(err)
+			}
+			if pane.Native() != tt.native {
+				t.Errorf("native = %v, want %v", pane.Native(), tt.native)
+			}
+			if problems := pane.modeProblems(); !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("problems = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
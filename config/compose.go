@@ -0,0 +1,271 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResetTag marks a value that replaces the inherited one instead of being
+// merged into it, e.g. `env: !reset {}` or a tab tagged `!reset`
+const ResetTag = "!reset"
+
+// CycleError reports a chain of extends/include references that loops
+// back on itself
+type CycleError struct {
+	Chain []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("extends/include cycle: %s", strings.Join(e.Chain, " -> "))
+}
+
+// SourceFile is a file read while composing a project: the project file
+// itself or one it extends or includes, with the contents that were used
+type SourceFile struct {
+	Path string
+	Data []byte
+}
+
+// ComposeProject loads the project file at path, renders it with vars, and
+// merges in the files it references with `extends:` and `include:`. The
+// result is a single YAML mapping node ready to be decoded into a Project,
+// along with every file that was read, the project file first.
+//
+// The extended project is the base, each include is merged over it in
+// order, and the file's own values are merged last. Mappings merge key by
+// key, tabs merge by name (matching tabs are merged, new ones appended),
+// other values replace what they inherit, and values tagged !reset always
+// replace.
+func ComposeProject(path string, vars Vars) (*yaml.Node, []SourceFile, error) {
+	var sources []SourceFile
+	node, err := composeFile(path, vars, nil, &sources)
+	if err != nil {
+		return nil, nil, err
+	}
+	stripResetTags(node)
+	return node, sources, nil
+}
+
+func composeFile(path string, vars Vars, stack []string, sources *[]SourceFile) (*yaml.Node, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, seen := range stack {
+		if seen == abs {
+			return nil, &CycleError{Chain: append(append([]string{}, stack...), abs)}
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+	*sources = append(*sources, SourceFile{Path: abs, Data: data})
+
+	data, err = RenderTemplate(abs, data, vars)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", abs, err)
+	}
+
+	own, err := parseMapping(abs, data)
+	if err != nil {
+		return nil, err
+	}
+
+	extends := takeKey(own, "extends")
+	include := takeKey(own, "include")
+
+	result := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	dir := filepath.Dir(abs)
+
+	if extends != nil && extends.Kind != yaml.ScalarNode {
+		return nil, referenceError(abs, extends, "extends must be a single project name or path")
+	}
+	if extends != nil && extends.Value != "" {
+		base, err := composeFile(resolveReference(extends.Value, dir), vars, stack, sources)
+		if err != nil {
+			return nil, err
+		}
+		result = base
+	}
+
+	if include != nil {
+		refs := []*yaml.Node{include}
+		if include.Kind == yaml.SequenceNode {
+			refs = include.Content
+		}
+		for _, ref := range refs {
+			if ref.Kind != yaml.ScalarNode {
+				return nil, referenceError(abs, ref, "include must be a project name or path, or a list of them")
+			}
+			if ref.Value == "" {
+				continue
+			}
+			fragment, err := composeFile(resolveReference(ref.Value, dir), vars, stack, sources)
+			if err != nil {
+				return nil, err
+			}
+			result = mergeNodes(result, fragment, "")
+		}
+	}
+
+	return mergeNodes(result, own, ""), nil
+}
+
+// referenceError reports an extends/include value of the wrong shape
+func referenceError(path string, node *yaml.Node, msg string) error {
+	return &ParseError{Path: path, Line: node.Line, Column: node.Column, Msg: msg}
+}
+
+// parseMapping decodes a YAML document and returns its top-level mapping.
+// An empty document yields an empty mapping.
+func parseMapping(path string, data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, newParseError(path, err)
+	}
+
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ParseError{Path: path, Line: root.Line, Column: root.Column, Msg: "project file must be a mapping"}
+	}
+	return root, nil
+}
+
+// resolveReference turns an extends/include value into a file path. Values
+// that look like paths are resolved relative to dir; anything else is a
+// project name.
+func resolveReference(ref string, dir string) string {
+	isPath := strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "./") ||
+		strings.HasPrefix(ref, "../") || strings.HasPrefix(ref, "~") ||
+		strings.HasPrefix(ref, "$")
+	for _, ext := range ProjectExtensions {
+		if strings.HasSuffix(ref, ext) {
+			isPath = true
+		}
+	}
+
+	if !isPath {
+		return ProjectPath(ref)
+	}
+
+	path := ExpandPath(ref)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
+
+// takeKey removes key from a mapping node and returns its value
+func takeKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// mergeNodes merges overlay into base and returns the result. key is the
+// mapping key the values belong to, which selects the tab merge rule.
+func mergeNodes(base, overlay *yaml.Node, key string) *yaml.Node {
+	if overlay.Tag == ResetTag {
+		return overlay
+	}
+
+	// An empty value (e.g. `env:` with only comments) keeps the inherited one
+	if overlay.Kind == yaml.ScalarNode && overlay.Tag == "!!null" {
+		return base
+	}
+
+	if base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode {
+		return mergeMappings(base, overlay)
+	}
+
+	if key == "tabs" && base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode {
+		return mergeTabs(base, overlay)
+	}
+
+	return overlay
+}
+
+func mergeMappings(base, overlay *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+
+		merged := false
+		for j := 0; j+1 < len(base.Content); j += 2 {
+			if base.Content[j].Value == key.Value {
+				base.Content[j+1] = mergeNodes(base.Content[j+1], value, key.Value)
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			base.Content = append(base.Content, key, value)
+		}
+	}
+	return base
+}
+
+// mergeTabs merges tabs with the same name and appends new ones
+func mergeTabs(base, overlay *yaml.Node) *yaml.Node {
+	for _, tab := range overlay.Content {
+		name := mappingValue(tab, "name")
+
+		merged := false
+		if name != "" {
+			for i, existing := range base.Content {
+				if mappingValue(existing, "name") == name {
+					base.Content[i] = mergeNodes(existing, tab, "")
+					merged = true
+					break
+				}
+			}
+		}
+
+		if !merged {
+			base.Content = append(base.Content, tab)
+		}
+	}
+	return base
+}
+
+// mappingValue returns the scalar value of key in a mapping node
+func mappingValue(mapping *yaml.Node, key string) string {
+	if mapping.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// stripResetTags removes the !reset markers once merging is done so the
+// node decodes normally. They are kept until then because a fragment's
+// resets must still apply when it is merged over the next base.
+func stripResetTags(node *yaml.Node) {
+	if node.Tag == ResetTag {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		stripResetTags(child)
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// composeFixture composes testdata/compose/<name>.yml into a project
+func composeFixture(t *testing.T, name string) (*Project, error) {
+	t.Helper()
+	node, _, err := ComposeProject(filepath.Join("testdata", "compose", name+".yml"), Vars{})
+	if err != nil {
+		return nil, err
+	}
+	var project Project
+	if err := node.Decode(&project); err != nil {
+		t.Fatal(err)
+	}
+	return &project, nil
+}
+
+func TestComposeProject(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		// tabs maps each tab name, in order, to its first pane's first command
+		tabs  [][]string
+		focus string
+	}{
+		{
+			name: "child",
+			env:  map[string]string{"A": "1", "B": "3"},
+			tabs: [][]string{
+				{"main", "vim"},
+				{"logs", "tail -f log/dev.log"},
+				{"server", "make run"},
+			},
+			focus: "main",
+		},
+		{
+			name: "reset",
+			env:  map[string]string{"C": "4"},
+			tabs: [][]string{{"only"}},
+		},
+		{
+			name: "included",
+			env:  map[string]string{"A": "1", "B": "from include", "D": "6"},
+			tabs: [][]string{
+				{"main", "vim"},
+				{"logs", "tail -f log/dev.log"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := composeFixture(t, tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if project.Name != tt.name {
+				t.Errorf("name = %q, want %q", project.Name, tt.name)
+			}
+			if project.Root != "~/code/app" {
+				t.Errorf("root = %q, want it inherited", project.Root)
+			}
+			if !reflect.DeepEqual(project.Env, tt.env) {
+				t.Errorf("env = %v, want %v", project.Env, tt.env)
+			}
+
+			var tabs [][]string
+			focus := ""
+			for _, tab := range project.Tabs {
+				got := []string{tab.Name}
+				if len(tab.Panes) > 0 && len(tab.Panes[0].Commands) > 0 {
+					got = append(got, tab.Panes[0].Commands[0])
+				}
+				tabs = append(tabs, got)
+				if tab.Focus {
+					focus = tab.Name
+				}
+			}
+			if !reflect.DeepEqual(tabs, tt.tabs) {
+				t.Errorf("tabs = %q, want %q", tabs, tt.tabs)
+			}
+			if focus != tt.focus {
+				t.Errorf("focused tab = %q, want %q", focus, tt.focus)
+			}
+		})
+	}
+}
+
+func TestComposeProjectSources(t *testing.T) {
+	_, sources, err := ComposeProject(filepath.Join("testdata", "compose", "included.yml"), Vars{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, source := range sources {
+		names = append(names, filepath.Base(source.Path))
+	}
+	if want := []string{"included.yml", "base.yml", "extra-env.yml"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sources = %q, want %q", names, want)
+	}
+}
+
+func TestComposeProjectCycle(t *testing.T) {
+	_, err := composeFixture(t, "cycle-a")
+
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("err = %v, want a CycleError", err)
+	}
+	var names []string
+	for _, path := range cycle.Chain {
+		names = append(names, filepath.Base(path))
+	}
+	if want := []string{"cycle-a.yml", "cycle-b.yml", "cycle-a.yml"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("chain = %q, want %q", names, want)
+	}
+}
+
+func TestComposeProjectBadReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   int
+		column int
+	}{
+		{name: "extends-list", line: 2, column: 10},
+		{name: "include-map", line: 4, column: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := composeFixture(t, tt.name)
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("err = %v, want a ParseError", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column {
+				t.Errorf("position = %d:%d, want %d:%d", parseErr.Line, parseErr.Column, tt.line, tt.column)
+			}
+		})
+	}
+}
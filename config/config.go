@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Project struct {
@@ -14,6 +15,23 @@ type Project struct {
 	DefaultLayout string            `yaml:"default_layout,omitempty"`
 	Tabs          []Tab             `yaml:"tabs"`
 	Env           map[string]string `yaml:"env,omitempty"`
+	Tags          []string          `yaml:"tags,omitempty"`
+
+	// Worktrees starts the project in the git worktree containing the
+	// current directory, with a session per worktree
+	Worktrees bool `yaml:"worktrees,omitempty"`
+	// WorktreeDir is where new worktrees are created, relative to Root.
+	// They go beside Root when it is empty.
+	WorktreeDir string `yaml:"worktree_dir,omitempty"`
+
+	ShellOptions `yaml:",inline"`
+
+	// Path is the file the project was loaded from
+	Path string `yaml:"-"`
+	// Sources are the files the project was composed from, as they were
+	// read: Path first, then the files it extends and includes, then its
+	// layout file
+	Sources []SourceFile `yaml:"-"`
 }
 
 type Tab struct {
@@ -21,25 +39,89 @@ type Tab struct {
 	Focus  bool     `yaml:"focus,omitempty"`
 	Layout string   `yaml:"layout,omitempty"`
 	Panes  []Pane   `yaml:"panes"`
+
+	ShellOptions `yaml:",inline"`
 }
 
 type Pane struct {
+	// Name identifies the pane for depends_on
+	Name     string   `yaml:"name,omitempty"`
 	Focus    bool     `yaml:"focus,omitempty"`
 	Commands []string `yaml:"commands,omitempty"`
 	Size     string   `yaml:"size,omitempty"`
 	Split    string   `yaml:"split,omitempty"`
+	// Mode is shell or native, see PaneModeShell and PaneModeNative
+	Mode string `yaml:"mode,omitempty"`
+	// Command is the command a native pane runs
+	Command        CommandLine `yaml:"command,omitempty"`
+	CloseOnExit    bool        `yaml:"close_on_exit,omitempty"`
+	StartSuspended bool        `yaml:"start_suspended,omitempty"`
+	Borderless     bool        `yaml:"borderless,omitempty"`
+	// Edit opens a file, relative to the project root, in the editor.
+	// A line number can follow a colon: src/main.go:42.
+	Edit string `yaml:"edit,omitempty"`
+
+	ShellOptions `yaml:",inline"`
+	// DependsOn names the panes whose ready probes must pass before this
+	// pane's commands run
+	DependsOn []string    `yaml:"depends_on,omitempty"`
+	Ready     *ReadyProbe `yaml:"ready,omitempty"`
 }
 
+// ProjectExtensions are the file extensions recognised as project files,
+// in order of precedence when both exist for the same name
+var ProjectExtensions = []string{".yaml", ".yml"}
+
+// ConfigDir returns the personal configuration directory. In order of
+// preference it is $ZELLIJINATOR_CONFIG_DIR, $XDG_CONFIG_HOME/zellijinator
+// (or ~/.config/zellijinator) when that directory exists, and finally
+// ~/.zellijinator.
 func ConfigDir() string {
+	if dir := os.Getenv("ZELLIJINATOR_CONFIG_DIR"); dir != "" {
+		return ExpandPath(dir)
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
+
+	xdgHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgHome == "" {
+		xdgHome = filepath.Join(home, ".config")
+	}
+	xdgDir := filepath.Join(xdgHome, "zellijinator")
+	if info, err := os.Stat(xdgDir); err == nil && info.IsDir() {
+		return xdgDir
+	}
+
 	return filepath.Join(home, ".zellijinator")
 }
 
+// ProjectPath returns the file defining the named project. Names may be
+// namespaced with slashes ("work/api" is work/api.yaml). The first search
+// path containing the project wins; if no file exists yet, the path where
+// a new project would be created in ConfigDir is returned.
 func ProjectPath(name string) string {
-	return filepath.Join(ConfigDir(), fmt.Sprintf("%s.yaml", name))
+	for _, dir := range SearchPaths() {
+		for _, ext := range ProjectExtensions {
+			candidate := filepath.Join(dir, filepath.FromSlash(name)+ext)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+	}
+	return filepath.Join(ConfigDir(), fmt.Sprintf("%s.yaml", filepath.FromSlash(name)))
+}
+
+// ExpandPath expands a leading ~ and any environment variables in path
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return os.ExpandEnv(path)
 }
 
 func EnsureConfigDir() error {
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EditTarget splits the pane's edit field into the file and the line
+// number after a final colon, 0 when there is none
+func (p Pane) EditTarget() (string, int) {
+	if i := strings.LastIndex(p.Edit, ":"); i >= 0 {
+		if line, err := strconv.Atoi(p.Edit[i+1:]); err == nil && line > 0 {
+			return p.Edit[:i], line
+		}
+	}
+	return p.Edit, 0
+}
+
+// EditorCommand returns a shell command opening the pane's edit file in
+// $EDITOR, at its line when one is given. Zellij's own edit panes can't
+// jump to a line, so those are started this way instead.
+func (p Pane) EditorCommand() string {
+	file, line := p.EditTarget()
+	command := `exec "${EDITOR:-vi}"`
+	if line > 0 {
+		command += fmt.Sprintf(" +%d", line)
+	}
+	return command + " " + ShellQuote(ExpandPath(file))
+}
+
+// editProblems returns what is wrong with the pane's edit field
+func (p Pane) editProblems() []string {
+	if p.Edit == "" {
+		return nil
+	}
+
+	var problems []string
+	if file, _ := p.EditTarget(); file == "" {
+		problems = append(problems, fmt.Sprintf("edit needs a file, got %q", p.Edit))
+	}
+	if len(p.Command) > 0 || len(p.Commands) > 0 || p.Mode != "" {
+		problems = append(problems, "an edit pane opens a file and can't also have command, commands or mode")
+	}
+	return problems
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		edit    string
+		file    string
+		line    int
+		command string
+	}{
+		{edit: "README.md", file: "README.md", command: `exec "${EDITOR:-vi}" 'README.md'`},
+		{edit: "src/main.go:12", file: "src/main.go", line: 12, command: `exec "${EDITOR:-vi}" +12 'src/main.go'`},
+		// Not a line number, so part of the file name
+		{edit: "notes:0", file: "notes:0", command: `exec "${EDITOR:-vi}" 'notes:0'`},
+		{edit: "notes:-3", file: "notes:-3", command: `exec "${EDITOR:-vi}" 'notes:-3'`},
+		{edit: "a:b", file: "a:b", command: `exec "${EDITOR:-vi}" 'a:b'`},
+		{edit: "a:b:7", file: "a:b", line: 7, command: `exec "${EDITOR:-vi}" +7 'a:b'`},
+		{edit: "~/it's.md:3", file: "~/it's.md", line: 3, command: `exec "${EDITOR:-vi}" +3 '` + home + `/it'\''s.md'`},
+		{edit: ":4", file: "", line: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.edit, func(t *testing.T) {
+			pane := Pane{Edit: tt.edit}
+			if file, line := pane.EditTarget(); file != tt.file || line != tt.line {
+				t.Errorf("EditTarget = %q, %d; want %q, %d", file, line, tt.file, tt.line)
+			}
+			if tt.command != "" && pane.EditorCommand() != tt.command {
+				t.Errorf("EditorCommand = %s, want %s", pane.EditorCommand(), tt.command)
+			}
+		})
+	}
+}
+
+func TestEditProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		pane     Pane
+		problems []string
+	}{
+		{name: "none", pane: Pane{Commands: []string{"vim"}}},
+		{name: "file", pane: Pane{Edit: "README.md:3"}},
+		{name: "no file", pane: Pane{Edit: ":3"}, problems: []string{`edit needs a file, got ":3"`}},
+		{
+			name:     "with commands",
+			pane:     Pane{Edit: "README.md", Commands: []string{"vim"}},
+			problems: []string{"an edit pane opens a file and can't also have command, commands or mode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if problems := tt.pane.editProblems(); !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("problems = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotFound is returned when no file defines the requested project
+var ErrNotFound = errors.New("project not found")
+
+// NotFoundError reports the project that couldn't be found. It matches
+// ErrNotFound with errors.Is.
+type NotFoundError struct {
+	// Name is the requested project, "." for a local project file
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Name == "." {
+		return fmt.Sprintf("no %s in this directory or any parent", LocalProjectFiles[0])
+	}
+	return fmt.Sprintf("project %q not found", e.Name)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ParseError reports a project file that isn't valid YAML or doesn't match
+// the project schema. Line and Column are 1-based and zero when unknown.
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Msg)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+	default:
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+}
+
+// ValidationError lists the problems found in a project that parsed
+// correctly but can't be turned into a layout
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// newParseError converts an error from yaml.v3 into a ParseError. Type
+// errors can hold several messages; the first one determines the position.
+func newParseError(path string, err error) *ParseError {
+	messages := []string{err.Error()}
+
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		messages = typeErr.Errors
+	}
+
+	parseErr := &ParseError{Path: path}
+	for i, msg := range messages {
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		if match := yamlLinePattern.FindStringSubmatch(msg); match != nil {
+			if i == 0 {
+				parseErr.Line, _ = strconv.Atoi(match[1])
+			}
+			msg = match[2]
+		}
+		messages[i] = msg
+	}
+	parseErr.Msg = strings.Join(messages, "; ")
+	return parseErr
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultGroupConcurrency is how many projects of a group start at once
+// when the group doesn't say
+const DefaultGroupConcurrency = 4
+
+// ErrGroupNotFound is returned when no file defines the requested group
+var ErrGroupNotFound = errors.New("group not found")
+
+// Group is a set of projects started and stopped together, defined in
+// groups/<name>.yaml in the config directory
+type Group struct {
+	Name string `yaml:"name,omitempty"`
+	// Primary is the project attached to once all have started, by
+	// default the first one
+	Primary string `yaml:"primary,omitempty"`
+	// Concurrency limits how many projects start at once
+	Concurrency int            `yaml:"concurrency,omitempty"`
+	Projects    []GroupProject `yaml:"projects"`
+
+	Path string `yaml:"-"`
+}
+
+// GroupProject is a member of a group with the arguments it is started
+// with. It can be written as just the project name.
+type GroupProject struct {
+	Name string            `yaml:"name"`
+	Args []string          `yaml:"args,omitempty"`
+	Vars map[string]string `yaml:"vars,omitempty"`
+}
+
+func (p *GroupProject) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		p.Name = value.Value
+		return nil
+	}
+	type plain GroupProject
+	return value.Decode((*plain)(p))
+}
+
+// StartVars returns the template variables the member is started with:
+// its own, with the settings in overrides taking precedence
+func (p GroupProject) StartVars(overrides map[string]string) Vars {
+	if len(overrides) == 0 {
+		return Vars{Args: p.Args, Settings: p.Vars}
+	}
+	settings := make(map[string]string, len(p.Vars)+len(overrides))
+	maps.Copy(settings, p.Vars)
+	maps.Copy(settings, overrides)
+	return Vars{Args: p.Args, Settings: settings}
+}
+
+// GroupsDir returns the directory group files are read from
+func GroupsDir() string {
+	return filepath.Join(ConfigDir(), "groups")
+}
+
+// ListGroups returns the names of the defined groups, sorted
+func ListGroups() ([]string, error) {
+	files, err := os.ReadDir(GroupsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		for _, ext := range ProjectExtensions {
+			name, ok := strings.CutSuffix(file.Name(), ext)
+			if ok && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadGroup reads and validates the named group
+func LoadGroup(name string) (*Group, error) {
+	for _, ext := range ProjectExtensions {
+		path := filepath.Join(GroupsDir(), name+ext)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var group Group
+		if err := yaml.Unmarshal(data, &group); err != nil {
+			return nil, newParseError(path, err)
+		}
+		group.Path = path
+		if group.Name == "" {
+			group.Name = name
+		}
+		if err := group.Validate(); err != nil {
+			return nil, err
+		}
+		return &group, nil
+	}
+	return nil, fmt.Errorf("%q: %w", name, ErrGroupNotFound)
+}
+
+// Validate checks the group lists projects and that its primary is one
+func (g *Group) Validate() error {
+	var problems []string
+
+	if len(g.Projects) == 0 {
+		problems = append(problems, "projects must list at least one project")
+	}
+	members := make(map[string]bool)
+	for i, project := range g.Projects {
+		switch {
+		case project.Name == "":
+			problems = append(problems, fmt.Sprintf("project %d has no name", i+1))
+		case project.Name == "." || strings.HasPrefix(project.Name, "@"):
+			problems = append(problems, fmt.Sprintf("project %d: %q can't be a group member", i+1, project.Name))
+		case members[project.Name]:
+			problems = append(problems, fmt.Sprintf("project %q is listed twice", project.Name))
+		}
+		members[project.Name] = true
+	}
+	if g.Primary != "" && !members[g.Primary] {
+		problems = append(problems, fmt.Sprintf("primary %q is not one of the projects", g.Primary))
+	}
+	if g.Concurrency < 0 {
+		problems = append(problems, fmt.Sprintf("concurrency must be positive, got %d", g.Concurrency))
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Path: g.Path, Problems: problems}
+	}
+	return nil
+}
+
+// PrimaryProject returns the project to attach to after starting the group
+func (g *Group) PrimaryProject() string {
+	if g.Primary != "" {
+		return g.Primary
+	}
+	return g.Projects[0].Name
+}
+
+// MaxConcurrency returns how many projects may start at once
+func (g *Group) MaxConcurrency() int {
+	if g.Concurrency > 0 {
+		return g.Concurrency
+	}
+	return DefaultGroupConcurrency
+}
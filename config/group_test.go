@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useGroupFixtures points the config directory at testdata/group
+func useGroupFixtures(t *testing.T) {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join("testdata", "group"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", dir)
+}
+
+func TestListGroups(t *testing.T) {
+	useGroupFixtures(t)
+
+	names, err := ListGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dev", "empty", "invalid"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("groups = %q, want %q", names, want)
+	}
+}
+
+func TestLoadGroup(t *testing.T) {
+	useGroupFixtures(t)
+
+	group, err := LoadGroup("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []GroupProject{
+		{Name: "api"},
+		{Name: "web", Args: []string{"staging"}, Vars: map[string]string{"port": "3000", "branch": "main"}},
+	}
+	if !reflect.DeepEqual(group.Projects, want) {
+		t.Errorf("projects = %+v, want %+v", group.Projects, want)
+	}
+	if group.Name != "dev" {
+		t.Errorf("name = %q, want dev", group.Name)
+	}
+	if group.PrimaryProject() != "web" {
+		t.Errorf("primary = %q, want web", group.PrimaryProject())
+	}
+	if group.MaxConcurrency() != 2 {
+		t.Errorf("concurrency = %d, want 2", group.MaxConcurrency())
+	}
+}
+
+func TestLoadGroupNotFound(t *testing.T) {
+	useGroupFixtures(t)
+
+	if _, err := LoadGroup("missing"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("err = %v, want ErrGroupNotFound", err)
+	}
+}
+
+func TestLoadGroupValidation(t *testing.T) {
+	useGroupFixtures(t)
+
+	tests := []struct {
+		name     string
+		problems []string
+	}{
+		{
+			name: "invalid",
+			problems: []string{
+				"project 2 has no name",
+				`project 3: "." can't be a group member`,
+				`project 4: "@other" can't be a group member`,
+				`project "api" is listed twice`,
+				`primary "docs" is not one of the projects`,
+				"concurrency must be positive, got -1",
+			},
+		},
+		{
+			name:     "empty",
+			problems: []string{"projects must list at least one project"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGroup(tt.name)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Problems, tt.problems) {
+				t.Errorf("problems =\n%q\nwant\n%q", validationErr.Problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestGroupProjectStartVars(t *testing.T) {
+	member := GroupProject{Name: "web", Args: []string{"staging"}, Vars: map[string]string{"port": "3000", "branch": "main"}}
+
+	vars := member.StartVars(map[string]string{"port": "4000", "debug": "1"})
+	want := map[string]string{"port": "4000", "branch": "main", "debug": "1"}
+	if !reflect.DeepEqual(vars.Settings, want) {
+		t.Errorf("settings = %v, want %v", vars.Settings, want)
+	}
+	if !reflect.DeepEqual(vars.Args, member.Args) {
+		t.Errorf("args = %q, want %q", vars.Args, member.Args)
+	}
+	if member.Vars["port"] != "3000" {
+		t.Errorf("the group's own vars were changed: %v", member.Vars)
+	}
+
+	if vars := member.StartVars(nil); !reflect.DeepEqual(vars.Settings, member.Vars) {
+		t.Errorf("settings without overrides = %v, want %v", vars.Settings, member.Vars)
+	}
+}
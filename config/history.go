@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Outcomes recorded in the history
+const (
+	OutcomeStarted  = "started"
+	OutcomeAttached = "attached"
+	OutcomeFailed   = "failed"
+)
+
+// historyLimit is the number of entries kept when the file is compacted
+const historyLimit = 500
+
+// HistoryEntry records one attempt to start a project
+type HistoryEntry struct {
+	Project string            `json:"project"`
+	Path    string            `json:"path"`
+	Local   bool              `json:"local,omitempty"`
+	Time    time.Time         `json:"time"`
+	Args    []string          `json:"args,omitempty"`
+	Vars    map[string]string `json:"vars,omitempty"`
+	// Worktree is the git worktree the project was started in, if any
+	Worktree string `json:"worktree,omitempty"`
+	// Instance is the suffix of a copy started with --new-instance or --as
+	Instance string `json:"instance,omitempty"`
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+}
+
+// StartVars returns the arguments the project was started with
+func (e HistoryEntry) StartVars() Vars {
+	vars := Vars{Args: e.Args, Settings: make(map[string]string)}
+	for key, value := range e.Vars {
+		vars.Settings[key] = value
+	}
+	return vars
+}
+
+// CommandArgs returns the arguments as they were given after the project
+// name, with key=value settings sorted by key
+func (e HistoryEntry) CommandArgs() []string {
+	args := append([]string{}, e.Args...)
+	keys := make([]string, 0, len(e.Vars))
+	for key := range e.Vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, key+"="+e.Vars[key])
+	}
+	return args
+}
+
+// StateDir returns $XDG_STATE_HOME/zellijinator, or
+// ~/.local/state/zellijinator when XDG_STATE_HOME isn't set
+func StateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "zellijinator")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "zellijinator")
+	}
+	return filepath.Join(home, ".local", "state", "zellijinator")
+}
+
+// HistoryPath returns the location of the history file
+func HistoryPath() string {
+	return filepath.Join(StateDir(), "history.jsonl")
+}
+
+// AppendHistory adds an entry to the history file. Writers take an
+// exclusive lock, so several terminals can record starts at once.
+func AppendHistory(entry HistoryEntry) error {
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return err
+	}
+
+	unlock, err := lockHistory()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(HistoryPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return compactHistory()
+}
+
+// ReadHistory returns the history, oldest first. Lines that can't be
+// parsed are skipped.
+func ReadHistory() ([]HistoryEntry, error) {
+	data, err := os.ReadFile(HistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// LastStarted returns when each project file was last started
+// successfully, keyed by path
+func LastStarted() map[string]time.Time {
+	entries, _ := ReadHistory()
+
+	last := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.Outcome == OutcomeFailed {
+			continue
+		}
+		if entry.Time.After(last[entry.Path]) {
+			last[entry.Path] = entry.Time
+		}
+	}
+	return last
+}
+
+// StartedInstances returns the instance suffixes projects were started
+// with, keyed by path
+func StartedInstances() map[string]map[string]bool {
+	entries, _ := ReadHistory()
+
+	instances := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if entry.Instance == "" {
+			continue
+		}
+		if instances[entry.Path] == nil {
+			instances[entry.Path] = make(map[string]bool)
+		}
+		instances[entry.Path][entry.Instance] = true
+	}
+	return instances
+}
+
+// compactHistory keeps the newest entries once the file has grown to
+// twice the limit. The caller must hold the history lock.
+func compactHistory() error {
+	entries, err := ReadHistory()
+	if err != nil || len(entries) < 2*historyLimit {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries[len(entries)-historyLimit:] {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	tmp := HistoryPath() + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, HistoryPath())
+}
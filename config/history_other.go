@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package config
+
+// lockHistory is a no-op where flock isn't available. Appends are still
+// single writes, so concurrent entries are rarely interleaved.
+func lockHistory() (func(), error) {
+	return func() {}, nil
+}
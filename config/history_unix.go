@@ -0,0 +1,26 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockHistory takes an exclusive lock on a file beside the history file
+// and returns a function that releases it
+func lockHistory() (func(), error) {
+	file, err := os.OpenFile(filepath.Join(StateDir(), "history.lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
@@ -1,32 +1,134 @@
 package config
 
 import (
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// ProjectFile describes a project definition found on a search path
+type ProjectFile struct {
+	// Name is the project name, namespaced by subdirectory ("work/api")
+	Name string
+	// Path is the file that defines the project
+	Path string
+	// SearchPath is the search path the file was found in
+	SearchPath string
+	// Shadowed lists lower-precedence files defining the same name
+	Shadowed []string
+}
+
+// reservedEntries are files and directories at the top of a search path
+// that hold zellijinator's own data rather than projects
+var reservedEntries = map[string]bool{
+	"config.yaml":  true,
+	"trusted.yaml": true,
+	"fragments":    true,
+	"templates":    true,
+	"groups":       true,
+}
+
 // ListProjects returns a list of all project names
 func ListProjects() ([]string, error) {
-	dir := ConfigDir()
-	
-	// Ensure config directory exists
-	if err := EnsureConfigDir(); err != nil {
-		return nil, err
-	}
-	
-	files, err := os.ReadDir(dir)
+	files, err := DiscoverProjects()
 	if err != nil {
 		return nil, err
 	}
-	
-	var projects []string
+
+	projects := make([]string, 0, len(files))
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".yaml") {
-			// Remove .yaml extension to get project name
-			projectName := strings.TrimSuffix(file.Name(), ".yaml")
-			projects = append(projects, projectName)
+		projects = append(projects, file.Name)
+	}
+	return projects, nil
+}
+
+// DiscoverProjects walks every search path and returns the projects found,
+// sorted by name. When several files define the same name, the one from
+// the earliest search path wins (and .yaml wins over .yml within a
+// directory); the others are recorded in Shadowed.
+func DiscoverProjects() ([]ProjectFile, error) {
+	// Ensure config directory exists
+	if err := EnsureConfigDir(); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*ProjectFile)
+	var names []string
+
+	for _, searchPath := range SearchPaths() {
+		found, err := scanSearchPath(searchPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range found {
+			if existing, ok := byName[file.Name]; ok {
+				existing.Shadowed = append(existing.Shadowed, file.Path)
+				continue
+			}
+			f := file
+			byName[file.Name] = &f
+			names = append(names, file.Name)
 		}
 	}
-	
+
+	sort.Strings(names)
+	projects := make([]ProjectFile, 0, len(names))
+	for _, name := range names {
+		projects = append(projects, *byName[name])
+	}
 	return projects, nil
-}
\ No newline at end of file
+}
+
+// scanSearchPath finds project files below dir. WalkDir visits entries in
+// lexical order, so name.yaml is always seen before name.yml.
+func scanSearchPath(dir string) ([]ProjectFile, error) {
+	var found []ProjectFile
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				// Search paths that don't exist are simply empty
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(entry.Name(), ".") || (!strings.Contains(rel, string(filepath.Separator)) && reservedEntries[entry.Name()]) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		for _, ext := range ProjectExtensions {
+			if strings.HasSuffix(entry.Name(), ext) {
+				found = append(found, ProjectFile{
+					Name:       filepath.ToSlash(strings.TrimSuffix(rel, ext)),
+					Path:       path,
+					SearchPath: dir,
+				})
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Find returns the file defining the named project, or ErrNotFound. The
+// name "." finds the nearest local project file from the current directory.
+func Find(name string) (string, error) {
+	if name == "." {
+		path, err := FindLocalProject("")
+		if err != nil {
+			return "", err
+		}
+		if path == "" {
+			return "", &NotFoundError{Name: name}
+		}
+		return path, nil
+	}
+
+	path := ProjectPath(name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", &NotFoundError{Name: name}
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+// Load reads the named project for commands that only inspect it. Template
+// variables that weren't given render as empty strings.
+func Load(name string) (*Project, error) {
+	return LoadWithVars(name, Vars{Partial: true})
+}
+
+// LoadWithVars reads the named project, rendering it with the given
+// runtime arguments
+func LoadWithVars(name string, vars Vars) (*Project, error) {
+	path, err := Find(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path, vars)
+}
+
+// LoadFile reads the project file at path. The file is rendered with vars,
+// composed with its extends/include files, decoded, given its defaults and
+// validated, so every command sees a project the same way.
+func LoadFile(path string, vars Vars) (*Project, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, &NotFoundError{Name: projectNameFromPath(path)}
+		}
+		return nil, err
+	}
+
+	node, sources, err := ComposeProject(path, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Project
+	if err := node.Decode(&project); err != nil {
+		return nil, newParseError(path, err)
+	}
+	project.Path = path
+	project.Sources = sources
+
+	if IsLocalProject(path) {
+		ApplyLocalDefaults(&project, path)
+	}
+	if project.Name == "" {
+		project.Name = projectNameFromPath(path)
+	}
+
+	if err := project.Validate(); err != nil {
+		return nil, err
+	}
+
+	// The layout file runs commands too, so it is read now along with the
+	// project and started from what was read
+	if project.Layout != "" {
+		if layoutPath, err := filepath.Abs(ExpandPath(project.Layout)); err == nil {
+			if data, err := os.ReadFile(layoutPath); err == nil {
+				project.Sources = append(project.Sources, SourceFile{Path: layoutPath, Data: data})
+			}
+		}
+	}
+	return &project, nil
+}
+
+// LayoutSource returns the project's layout file as it was loaded, or nil
+// when it has none or it couldn't be read
+func (p *Project) LayoutSource() *SourceFile {
+	if p.Layout == "" || len(p.Sources) < 2 {
+		return nil
+	}
+	layoutPath, err := filepath.Abs(ExpandPath(p.Layout))
+	if err != nil {
+		return nil
+	}
+	if last := p.Sources[len(p.Sources)-1]; last.Path == layoutPath {
+		return &last
+	}
+	return nil
+}
+
+// Save writes project as the named project file, creating namespace
+// directories as needed
+func Save(name string, project *Project) error {
+	path := ProjectPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(project); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	project.Path = path
+	return nil
+}
+
+// SessionNameOrDefault returns the Zellij session name, which defaults to
+// the project name with characters Zellij doesn't allow replaced
+func (p *Project) SessionNameOrDefault() string {
+	if p.SessionName != "" {
+		return p.SessionName
+	}
+	return SanitizeSessionName(p.Name)
+}
+
+// ResolvedRoot returns the project root with ~ and environment variables
+// expanded
+func (p *Project) ResolvedRoot() string {
+	if p.Root == "" {
+		return ""
+	}
+	return ExpandPath(p.Root)
+}
+
+// Validate checks the values a layout is generated from
+func (p *Project) Validate() error {
+	var problems []string
+
+	// A derived session name is sanitized, but one that was set must be
+	// fixed in the file. Length depends on the machine, so start checks it.
+	if p.SessionName != "" {
+		if sanitized := SanitizeSessionName(p.SessionName); sanitized != p.SessionName {
+			problems = append(problems, fmt.Sprintf("session_name %q contains characters Zellij doesn't allow (/, \\, whitespace); use %q", p.SessionName, sanitized))
+		}
+	}
+
+	for i, tab := range p.Tabs {
+		tabName := tab.Name
+		if tabName == "" {
+			tabName = fmt.Sprintf("#%d", i+1)
+		}
+
+		for j, pane := range tab.Panes {
+			where := fmt.Sprintf("tab %s, pane %d", tabName, j+1)
+
+			if pane.Split != "" && pane.Split != "horizontal" && pane.Split != "vertical" {
+				problems = append(problems, fmt.Sprintf("%s: split must be horizontal or vertical, got %q", where, pane.Split))
+			}
+
+			if pane.Size != "" {
+				size, err := strconv.Atoi(strings.TrimSpace(pane.Size))
+				if err != nil || size < 1 || size > 100 {
+					problems = append(problems, fmt.Sprintf("%s: size must be a percentage from 1 to 100, got %q", where, pane.Size))
+				}
+			}
+
+			for _, problem := range append(pane.modeProblems(), pane.editProblems()...) {
+				problems = append(problems, fmt.Sprintf("%s: %s", where, problem))
+			}
+
+			if pane.Ready != nil {
+				for _, problem := range pane.Ready.problems() {
+					problems = append(problems, fmt.Sprintf("%s: %s", where, problem))
+				}
+			}
+		}
+	}
+
+	problems = append(problems, p.dependencyProblems()...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Path: p.Path, Problems: problems}
+	}
+	return nil
+}
+
+// projectNameFromPath derives a project name from its file name
+func projectNameFromPath(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range ProjectExtensions {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useFixtures points the config directory and search path at
+// testdata/load, so personal/ is ConfigDir and shared/ the only search path
+func useFixtures(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.Abs(filepath.Join("testdata", "load"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", filepath.Join(dir, "personal"))
+	t.Setenv("ZELLIJINATOR_PATH", filepath.Join(dir, "shared"))
+	return dir
+}
+
+// chdir changes the working directory for the rest of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+func TestFind(t *testing.T) {
+	dir := useFixtures(t)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"api", "personal/api.yaml"},
+		{"work/api", "personal/work/api.yaml"},
+		{"shared-only", "shared/shared-only.yml"},
+		{"both", "personal/both.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Find(tt.name)
+			if err != nil {
+				t.Fatalf("Find(%q): %v", tt.name, err)
+			}
+			if want := filepath.Join(dir, tt.want); got != want {
+				t.Errorf("Find(%q) = %s, want %s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestFindNotFound(t *testing.T) {
+	useFixtures(t)
+
+	_, err := Find("missing")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.Name != "missing" {
+		t.Fatalf("Find(missing) error = %v, want NotFoundError for missing", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find(missing) error doesn't match ErrNotFound")
+	}
+}
+
+func TestFindLocal(t *testing.T) {
+	dir := useFixtures(t)
+	chdir(t, filepath.Join(dir, "local", "sub"))
+
+	got, err := Find(".")
+	if err != nil {
+		t.Fatalf("Find(.): %v", err)
+	}
+	if want := filepath.Join(dir, "local", ".zellijinator.yml"); got != want {
+		t.Errorf("Find(.) = %s, want %s", got, want)
+	}
+
+	project, err := LoadFile(got, Vars{})
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if project.Name != "local" || project.Root != filepath.Join(dir, "local") {
+		t.Errorf("local defaults: name %q root %q, want local and the file's directory", project.Name, project.Root)
+	}
+}
+
+func TestLoadParseError(t *testing.T) {
+	useFixtures(t)
+
+	tests := []struct {
+		name string
+		line int
+	}{
+		{"broken", 5},
+		{"wrongtype", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.name)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Load(%q) error = %v, want ParseError", tt.name, err)
+			}
+			if parseErr.Line != tt.line {
+				t.Errorf("Load(%q) error line = %d, want %d (%v)", tt.name, parseErr.Line, tt.line, parseErr)
+			}
+			if filepath.Base(parseErr.Path) != tt.name+".yaml" {
+				t.Errorf("Load(%q) error path = %s", tt.name, parseErr.Path)
+			}
+		})
+	}
+}
+
+func TestLoadValidationError(t *testing.T) {
+	useFixtures(t)
+
+	_, err := Load("invalid")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Load(invalid) error = %v, want ValidationError", err)
+	}
+
+	want := []string{
+		`tab main, pane 1: split must be horizontal or vertical, got "diagonal"`,
+		`tab main, pane 2: size must be a percentage from 1 to 100, got "150"`,
+		`tab #2, pane 1: mode must be shell or native, got "sideways"`,
+	}
+	if !reflect.DeepEqual(validationErr.Problems, want) {
+		t.Errorf("problems =\n%q\nwant\n%q", validationErr.Problems, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	useFixtures(t)
+
+	project, err := Load("api")
+	if err != nil {
+		t.Fatalf("Load(api): %v", err)
+	}
+	if project.Name != "api" || project.Root != "/srv/api" || len(project.Tabs) != 1 {
+		t.Errorf("Load(api) = %+v", project)
+	}
+	if project.SessionNameOrDefault() != "api" {
+		t.Errorf("session name = %q, want api", project.SessionNameOrDefault())
+	}
+}
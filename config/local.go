@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LocalProjectFiles are the file names looked up when searching for a
+// project definition committed alongside the code it describes
+var LocalProjectFiles = []string{".zellijinator.yml", ".zellijinator.yaml"}
+
+// FindLocalProject walks up from dir looking for a local project file.
+// It returns the path of the first file found, or an empty string if
+// neither dir nor any of its parents contain one.
+func FindLocalProject(dir string) (string, error) {
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = cwd
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range LocalProjectFiles {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// IsLocalProject reports whether path points at a repository-local project
+// file rather than one stored in the config directory
+func IsLocalProject(path string) bool {
+	base := filepath.Base(path)
+	for _, name := range LocalProjectFiles {
+		if base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyLocalDefaults fills in the fields a local project file may leave out.
+// The root defaults to the directory holding the file, relative roots are
+// resolved against it, and the name defaults to that directory's name.
+func ApplyLocalDefaults(project *Project, path string) {
+	dir := filepath.Dir(path)
+
+	if project.Name == "" {
+		project.Name = filepath.Base(dir)
+	}
+
+	if project.Root == "" {
+		project.Root = dir
+	} else if !filepath.IsAbs(project.Root) && project.Root[0] != '~' && project.Root[0] != '$' {
+		project.Root = filepath.Join(dir, project.Root)
+	}
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReadyTimeout is how long a pane waits for a dependency when its
+// ready probe doesn't set a timeout
+const DefaultReadyTimeout = 60 * time.Second
+
+// ReadyProbe says when a pane is ready for the panes that depend on it.
+// Every check that is set must pass.
+type ReadyProbe struct {
+	// Port is a TCP port on localhost, or host:port, accepting connections
+	Port string `yaml:"port,omitempty"`
+	// File must exist, relative to the project root
+	File string `yaml:"file,omitempty"`
+	// Command must exit 0, run with sh -c in the project root
+	Command string    `yaml:"command,omitempty"`
+	Log     *LogProbe `yaml:"log,omitempty"`
+	// Timeout is a duration such as 30s or 2m, DefaultReadyTimeout if empty
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// LogProbe waits for a line matching a regular expression to be written
+// to a log file
+type LogProbe struct {
+	File  string `yaml:"file"`
+	Match string `yaml:"match"`
+	// Offset is where in the file matching starts, taken when the layout
+	// is generated. When negative, only lines written after waiting
+	// starts count.
+	Offset int64 `yaml:"-"`
+}
+
+// LogOffset returns the size of the log file at path, relative to root,
+// or 0 when it doesn't exist yet
+func LogOffset(root string, path string) int64 {
+	path = ExpandPath(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.Size()
+	}
+	return 0
+}
+
+// TimeoutDuration returns the probe's timeout
+func (r ReadyProbe) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(r.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultReadyTimeout
+}
+
+// Address returns the host:port the port check connects to
+func (r ReadyProbe) Address() string {
+	if strings.Contains(r.Port, ":") {
+		return r.Port
+	}
+	return net.JoinHostPort("localhost", r.Port)
+}
+
+// Describe lists the probe's checks for the waiting banner
+func (r ReadyProbe) Describe() string {
+	var checks []string
+	if r.Port != "" {
+		checks = append(checks, "port "+r.Address())
+	}
+	if r.File != "" {
+		checks = append(checks, "file "+r.File)
+	}
+	if r.Command != "" {
+		checks = append(checks, "command "+r.Command)
+	}
+	if r.Log != nil {
+		checks = append(checks, fmt.Sprintf("/%s/ in %s", r.Log.Match, r.Log.File))
+	}
+	return strings.Join(checks, ", ")
+}
+
+// problems returns what is wrong with the probe
+func (r ReadyProbe) problems() []string {
+	var problems []string
+	if r.Port == "" && r.File == "" && r.Command == "" && r.Log == nil {
+		problems = append(problems, "ready needs at least one of port, file, command or log")
+	}
+	if r.Port != "" {
+		port := r.Port
+		if strings.Contains(port, ":") {
+			var err error
+			if _, port, err = net.SplitHostPort(port); err != nil {
+				problems = append(problems, fmt.Sprintf("ready port must be a port or host:port, got %q", r.Port))
+				port = ""
+			}
+		}
+		if n, err := strconv.Atoi(port); port != "" && (err != nil || n < 1 || n > 65535) {
+			problems = append(problems, fmt.Sprintf("ready port must be from 1 to 65535, got %q", r.Port))
+		}
+	}
+	if r.Log != nil {
+		if r.Log.File == "" || r.Log.Match == "" {
+			problems = append(problems, "ready log needs both file and match")
+		} else if _, err := regexp.Compile(r.Log.Match); err != nil {
+			problems = append(problems, fmt.Sprintf("ready log match is not a valid regular expression: %v", err))
+		}
+	}
+	if r.Timeout != "" {
+		if d, err := time.ParseDuration(r.Timeout); err != nil || d <= 0 {
+			problems = append(problems, fmt.Sprintf("ready timeout must be a duration such as 30s or 2m, got %q", r.Timeout))
+		}
+	}
+	return problems
+}
+
+// paneRef identifies a pane by its tab and position
+type paneRef struct {
+	tab  int
+	pane int
+}
+
+// findPane resolves a depends_on name used in the given tab: a pane of
+// that tab, otherwise the one pane with that name in another tab. It
+// returns the number of panes that matched, 1 when the name resolved.
+func (p *Project) findPane(tab int, name string) (paneRef, int) {
+	for j, pane := range p.Tabs[tab].Panes {
+		if pane.Name == name {
+			return paneRef{tab, j}, 1
+		}
+	}
+
+	var found paneRef
+	matches := 0
+	for i, other := range p.Tabs {
+		if i == tab {
+			continue
+		}
+		for j, pane := range other.Panes {
+			if pane.Name == name {
+				found = paneRef{i, j}
+				matches++
+			}
+		}
+	}
+	return found, matches
+}
+
+// dependencies returns the panes ref depends on that could be resolved
+func (p *Project) dependencies(ref paneRef) []paneRef {
+	var deps []paneRef
+	for _, name := range p.Tabs[ref.tab].Panes[ref.pane].DependsOn {
+		if dep, matches := p.findPane(ref.tab, name); matches == 1 {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// PaneDependencies returns the panes the pane at tab, pane waits for:
+// those it depends on and, in turn, the ones they depend on,
+// dependencies first
+func (p *Project) PaneDependencies(tab int, pane int) []Pane {
+	seen := make(map[paneRef]bool)
+	var deps []Pane
+
+	var visit func(ref paneRef)
+	visit = func(ref paneRef) {
+		for _, dep := range p.dependencies(ref) {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			visit(dep)
+			deps = append(deps, p.Tabs[dep.tab].Panes[dep.pane])
+		}
+	}
+	visit(paneRef{tab, pane})
+	return deps
+}
+
+// dependencyProblems checks pane names are unique within their tab and
+// that depends_on refers to existing panes without forming a cycle
+func (p *Project) dependencyProblems() []string {
+	var problems []string
+
+	for i, tab := range p.Tabs {
+		count := make(map[string]int)
+		for _, pane := range tab.Panes {
+			if pane.Name != "" {
+				count[pane.Name]++
+			}
+		}
+		for _, pane := range tab.Panes {
+			if count[pane.Name] > 1 {
+				problems = append(problems, fmt.Sprintf("tab %s: pane name %q is used %d times", tabLabel(tab, i), pane.Name, count[pane.Name]))
+				count[pane.Name] = 0
+			}
+		}
+
+		for _, pane := range tab.Panes {
+			for _, name := range pane.DependsOn {
+				switch _, matches := p.findPane(i, name); {
+				case matches == 0:
+					problems = append(problems, fmt.Sprintf("tab %s: depends_on refers to unknown pane %q", tabLabel(tab, i), name))
+				case matches > 1:
+					problems = append(problems, fmt.Sprintf("tab %s: depends_on %q matches panes in %d other tabs; rename them so it is unique", tabLabel(tab, i), name, matches))
+				}
+			}
+		}
+	}
+
+	// Walk the graph from each pane, reporting the first cycle found
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[paneRef]int)
+	var path []paneRef
+	var visit func(ref paneRef) bool
+	visit = func(ref paneRef) bool {
+		switch state[ref] {
+		case visiting:
+			var names []string
+			for i := len(path) - 1; i >= 0; i-- {
+				names = append([]string{p.Tabs[path[i].tab].Panes[path[i].pane].Name}, names...)
+				if path[i] == ref {
+					break
+				}
+			}
+			names = append(names, p.Tabs[ref.tab].Panes[ref.pane].Name)
+			problems = append(problems, fmt.Sprintf("depends_on forms a cycle: %s", strings.Join(names, " -> ")))
+			return true
+		case done:
+			return false
+		}
+		state[ref] = visiting
+		path = append(path, ref)
+		for _, dep := range p.dependencies(ref) {
+			if visit(dep) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		state[ref] = done
+		return false
+	}
+	for i, tab := range p.Tabs {
+		for j := range tab.Panes {
+			if visit(paneRef{i, j}) {
+				return problems
+			}
+		}
+	}
+	return problems
+}
+
+// tabLabel names a tab in messages, by position if it has no name
+func tabLabel(tab Tab, index int) string {
+	if tab.Name != "" {
+		return tab.Name
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
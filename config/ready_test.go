@@ -0,0 +1,219 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseProject decodes a project from YAML without loading or validating it
+func parseProject(t *testing.T, data string) *Project {
+	t.Helper()
+	var project Project
+	if err := yaml.Unmarshal([]byte(data), &project); err != nil {
+		t.Fatal(err)
+	}
+	return &project
+}
+
+func TestDependencyProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  string
+		problems []string
+	}{
+		{
+			name: "valid",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: db
+      - name: web
+        depends_on: [db, worker]
+  - name: jobs
+    panes:
+      - name: worker
+        depends_on: [db]
+      - name: web
+`,
+		},
+		{
+			name: "duplicate names",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: web
+      - name: web
+      - name: web
+      - name: db
+  - panes:
+      - name: db
+      - name: db
+`,
+			problems: []string{
+				`tab main: pane name "web" is used 3 times`,
+				`tab #2: pane name "db" is used 2 times`,
+			},
+		},
+		{
+			name: "unknown pane",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: web
+        depends_on: [db]
+`,
+			problems: []string{`tab main: depends_on refers to unknown pane "db"`},
+		},
+		{
+			name: "ambiguous pane in other tabs",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: web
+        depends_on: [db]
+  - name: a
+    panes:
+      - name: db
+  - name: b
+    panes:
+      - name: db
+`,
+			problems: []string{`tab main: depends_on "db" matches panes in 2 other tabs; rename them so it is unique`},
+		},
+		{
+			name: "cycle",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: start
+        depends_on: [a]
+      - name: a
+        depends_on: [b]
+      - name: b
+        depends_on: [c]
+  - name: other
+    panes:
+      - name: c
+        depends_on: [a]
+`,
+			problems: []string{"depends_on forms a cycle: a -> b -> c -> a"},
+		},
+		{
+			name: "depends on itself",
+			project: `
+tabs:
+  - name: main
+    panes:
+      - name: web
+        depends_on: [web]
+`,
+			problems: []string{"depends_on forms a cycle: web -> web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := parseProject(t, tt.project).dependencyProblems()
+			if !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("problems =\n%q\nwant\n%q", problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestFindPane(t *testing.T) {
+	project := parseProject(t, `
+tabs:
+  - name: main
+    panes:
+      - name: db
+      - name: web
+  - name: jobs
+    panes:
+      - name: worker
+      - name: db
+  - name: logs
+    panes:
+      - name: worker
+`)
+
+	tests := []struct {
+		tab     int
+		name    string
+		ref     paneRef
+		matches int
+	}{
+		{tab: 0, name: "web", ref: paneRef{0, 1}, matches: 1},
+		// A pane in the same tab wins over one elsewhere
+		{tab: 1, name: "db", ref: paneRef{1, 1}, matches: 1},
+		{tab: 2, name: "db", matches: 2},
+		{tab: 0, name: "worker", matches: 2},
+		{tab: 1, name: "missing", matches: 0},
+	}
+
+	for _, tt := range tests {
+		ref, matches := project.findPane(tt.tab, tt.name)
+		if matches != tt.matches || (matches == 1 && ref != tt.ref) {
+			t.Errorf("findPane(%d, %q) = %v, %d; want %v, %d", tt.tab, tt.name, ref, matches, tt.ref, tt.matches)
+		}
+	}
+}
+
+func TestPaneDependencies(t *testing.T) {
+	project := parseProject(t, `
+tabs:
+  - name: main
+    panes:
+      - name: db
+      - name: migrate
+        depends_on: [db]
+      - name: web
+        depends_on: [migrate, cache, db]
+  - name: services
+    panes:
+      - name: cache
+`)
+
+	var names []string
+	for _, pane := range project.PaneDependencies(0, 2) {
+		names = append(names, pane.Name)
+	}
+	if want := []string{"db", "migrate", "cache"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("dependencies = %q, want %q", names, want)
+	}
+
+	if deps := project.PaneDependencies(0, 0); len(deps) != 0 {
+		t.Errorf("db has dependencies %v", deps)
+	}
+}
+
+func TestReadyProbeProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		probe    ReadyProbe
+		problems []string
+	}{
+		{name: "port", probe: ReadyProbe{Port: "5432"}},
+		{name: "host and port", probe: ReadyProbe{Port: "db:5432", Timeout: "2m"}},
+		{name: "empty", problems: []string{"ready needs at least one of port, file, command or log"}},
+		{name: "port out of range", probe: ReadyProbe{Port: "70000"}, problems: []string{`ready port must be from 1 to 65535, got "70000"`}},
+		{name: "bad host and port", probe: ReadyProbe{Port: "a:b:c"}, problems: []string{`ready port must be a port or host:port, got "a:b:c"`}},
+		{name: "log without match", probe: ReadyProbe{Log: &LogProbe{File: "dev.log"}}, problems: []string{"ready log needs both file and match"}},
+		{name: "bad timeout", probe: ReadyProbe{File: "x", Timeout: "soon"}, problems: []string{`ready timeout must be a duration such as 30s or 2m, got "soon"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if problems := tt.probe.problems(); !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("problems = %q, want %q", problems, tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RewriteProjectName returns the project file data with its name set to
+// newName. A session_name equal to the old name is treated as derived from
+// it and renamed too. Only the values themselves are replaced in the
+// original text, so comments and formatting are kept. In a template the
+// {{ }} actions are masked while the file is parsed, and a value holding
+// one is replaced as a whole.
+func RewriteProjectName(data []byte, fileName string, newName string) ([]byte, error) {
+	parsed := data
+	if IsTemplate(data) {
+		parsed = maskTemplateActions(data)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(parsed, &doc); err != nil {
+		return nil, newParseError("", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("not a project file")
+	}
+	mapping := doc.Content[0]
+
+	var nameNode, sessionNode *yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		switch mapping.Content[i].Value {
+		case "name":
+			nameNode = mapping.Content[i+1]
+		case "session_name":
+			sessionNode = mapping.Content[i+1]
+		}
+	}
+
+	oldName := fileName
+	if nameNode != nil && nameNode.Value != "" {
+		oldName = nameNode.Value
+	}
+
+	var replace []*yaml.Node
+	if nameNode != nil {
+		replace = append(replace, nameNode)
+	}
+	if sessionNode != nil && (sessionNode.Value == oldName || sessionNode.Value == fileName) {
+		replace = append(replace, sessionNode)
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for _, node := range replace {
+		if err := replaceScalar(lines, node, newName); err != nil {
+			return nil, err
+		}
+	}
+	return bytes.Join(lines, nil), nil
+}
+
+// replaceScalar rewrites a single-line scalar in place, keeping its quoting
+func replaceScalar(lines [][]byte, node *yaml.Node, value string) error {
+	if node.Kind != yaml.ScalarNode || node.Line < 1 || node.Line > len(lines) {
+		return fmt.Errorf("line %d: expected a single value", node.Line)
+	}
+	line := string(lines[node.Line-1])
+	start := node.Column - 1
+	if start < 0 || start > len(line) {
+		return fmt.Errorf("line %d: unexpected value position", node.Line)
+	}
+
+	var end int
+	var replacement string
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		end = closingQuote(line, start, '"')
+		replacement = strconv.Quote(value)
+	case yaml.SingleQuotedStyle:
+		end = closingQuote(line, start, '\'')
+		replacement = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case 0:
+		end = start + len(node.Value)
+		replacement = value
+	default:
+		return fmt.Errorf("line %d: multi-line values can't be rewritten", node.Line)
+	}
+	if end < 0 || end > len(line) {
+		return fmt.Errorf("line %d: unexpected value position", node.Line)
+	}
+
+	lines[node.Line-1] = []byte(line[:start] + replacement + line[end:])
+	return nil
+}
+
+// closingQuote returns the index just past the quote closing the string
+// that starts at start
+func closingQuote(line string, start int, quote byte) int {
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case quote == '"' && line[i] == '\\':
+			i++
+		case quote == '\'' && line[i] == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case line[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+var templateActionPattern = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// maskTemplateActions replaces each {{ }} action with underscores of the
+// same length, keeping line breaks, so the file parses as YAML with every
+// value where it was. A line holding only actions becomes a comment.
+func maskTemplateActions(data []byte) []byte {
+	masked := templateActionPattern.ReplaceAllFunc(data, func(action []byte) []byte {
+		out := make([]byte, len(action))
+		for i, b := range action {
+			if b == '\n' {
+				out[i] = b
+			} else {
+				out[i] = '_'
+			}
+		}
+		return out
+	})
+
+	lines := bytes.SplitAfter(masked, []byte("\n"))
+	original := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		trimmed := bytes.TrimSpace(original[i])
+		if len(bytes.Trim(line, "_ \t\r\n")) == 0 && len(trimmed) > 0 &&
+			(bytes.HasPrefix(trimmed, []byte("{{")) || bytes.HasSuffix(trimmed, []byte("}}"))) {
+			indent := len(line) - len(bytes.TrimLeft(line, " \t"))
+			line[indent] = '#'
+		}
+	}
+	return masked
+}
@@ -0,0 +1,105 @@
+package config
+
+import "testing"
+
+func TestRewriteProjectName(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "unquoted",
+			data: "# The API\nname: api # keep this\nroot: ~/api\n",
+			want: "# The API\nname: billing # keep this\nroot: ~/api\n",
+		},
+		{
+			name: "double quoted",
+			data: "name: \"api\"\n",
+			want: "name: \"billing\"\n",
+		},
+		{
+			name: "single quoted",
+			data: "name: 'api'   # quoted\n",
+			want: "name: 'billing'   # quoted\n",
+		},
+		{
+			name: "no name",
+			data: "root: ~/api\n",
+			want: "root: ~/api\n",
+		},
+		{
+			name: "derived session name",
+			data: "name: api\nsession_name: api\n",
+			want: "name: billing\nsession_name: billing\n",
+		},
+		{
+			name: "session name from the file name",
+			data: "session_name: 'api'\n",
+			want: "session_name: 'billing'\n",
+		},
+		{
+			name: "explicit session name",
+			data: "name: api\nsession_name: backend\n",
+			want: "name: billing\nsession_name: backend\n",
+		},
+		{
+			name: "brackets kept",
+			data: "name: api\ntabs:\n  - panes:\n      - commands: [\"echo [[ .Name ]]\", \"docker ps --format '{{.Names}}'\"]\n",
+			want: "name: billing\ntabs:\n  - panes:\n      - commands: [\"echo [[ .Name ]]\", \"docker ps --format '{{.Names}}'\"]\n",
+		},
+		{
+			name: "template actions",
+			data: "# zellijinator: template\n" +
+				"{{- $branch := var \"branch\" | default \"main\" }}\n" +
+				"name: api\n" +
+				"session_name: api-{{ $branch }}\n" +
+				"root: {{ env \"HOME\" }}/api # home\n" +
+				"tabs:\n" +
+				"  - name: {{ arg 0 }}\n",
+			want: "# zellijinator: template\n" +
+				"{{- $branch := var \"branch\" | default \"main\" }}\n" +
+				"name: billing\n" +
+				"session_name: api-{{ $branch }}\n" +
+				"root: {{ env \"HOME\" }}/api # home\n" +
+				"tabs:\n" +
+				"  - name: {{ arg 0 }}\n",
+		},
+		{
+			name: "templated name",
+			data: "# zellijinator: template\nname: \"{{ var \\\"prefix\\\" }}-api\"\nroot: ~/api\n",
+			want: "# zellijinator: template\nname: \"billing\"\nroot: ~/api\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteProjectName([]byte(tt.data), "api", "billing")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("rewritten =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteProjectNameErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not a mapping", data: "- api\n"},
+		{name: "invalid yaml", data: "name: [api\n"},
+		{name: "multi-line name", data: "name: |\n  api\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RewriteProjectName([]byte(tt.data), "api", "billing"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
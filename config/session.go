@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSocketPath is the longest path a unix socket can have (sun_path)
+const maxSocketPath = 108
+
+// socketContractDir is the directory Zellij adds under its socket dir
+const socketContractDir = "contract_version_1"
+
+// SessionNameError reports a session name Zellij can't use
+type SessionNameError struct {
+	Name    string
+	Problem string
+	// Suggestion is a name that would work, if one can be derived
+	Suggestion string
+}
+
+func (e *SessionNameError) Error() string {
+	return fmt.Sprintf("session name %q %s", e.Name, e.Problem)
+}
+
+// SessionCollisionError reports two project files with the same session
+// name, so starting one would attach to the other's session
+type SessionCollisionError struct {
+	Session string
+	Paths   []string
+}
+
+func (e *SessionCollisionError) Error() string {
+	return fmt.Sprintf("session name %q is used by %s", e.Session, strings.Join(e.Paths, " and "))
+}
+
+// SocketDir returns the directory Zellij creates session sockets in:
+// $ZELLIJ_SOCKET_DIR, $XDG_RUNTIME_DIR/zellij or /tmp/zellij-<uid>
+func SocketDir() string {
+	dir := os.Getenv("ZELLIJ_SOCKET_DIR")
+	if dir == "" {
+		if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+			dir = filepath.Join(runtime, "zellij")
+		} else {
+			dir = filepath.Join(os.TempDir(), fmt.Sprintf("zellij-%d", os.Getuid()))
+		}
+	}
+	return filepath.Join(dir, socketContractDir)
+}
+
+// MaxSessionNameLength returns the longest session name whose socket path
+// fits in the current socket directory
+func MaxSessionNameLength() int {
+	return maxSocketPath - len(SocketDir()) - 2
+}
+
+// SanitizeSessionName replaces the characters Zellij doesn't allow in
+// session names, such as the / in namespaced project names, with -
+func SanitizeSessionName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	return sanitized
+}
+
+// CheckSessionName reports whether Zellij can use name as a session name
+func CheckSessionName(name string) error {
+	if name == "" {
+		return &SessionNameError{Name: name, Problem: "is empty"}
+	}
+	if sanitized := SanitizeSessionName(name); sanitized != name {
+		return &SessionNameError{Name: name, Problem: "contains characters Zellij doesn't allow (/, \\, whitespace)", Suggestion: sanitized}
+	}
+
+	max := MaxSessionNameLength()
+	if max < 1 {
+		return &SessionNameError{
+			Name:    name,
+			Problem: fmt.Sprintf("can't be used: the socket directory %s leaves no room for a name; set ZELLIJ_SOCKET_DIR to a shorter path", SocketDir()),
+		}
+	}
+	if len(name) > max {
+		return &SessionNameError{
+			Name:       name,
+			Problem:    fmt.Sprintf("is too long: sockets in %s allow at most %d bytes, it has %d", SocketDir(), max, len(name)),
+			Suggestion: truncateBytes(name, max),
+		}
+	}
+	return nil
+}
+
+// truncateBytes cuts s to at most n bytes without splitting a character
+func truncateBytes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// SessionCollisions returns the session names shared by more than one
+// project file, including the local project found from the current
+// directory. Files that fail to load are skipped.
+func SessionCollisions() ([]*SessionCollisionError, error) {
+	files, err := DiscoverProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files)+1)
+	if local, _ := FindLocalProject(""); local != "" {
+		paths = append(paths, local)
+	}
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	bySession := make(map[string][]string)
+	var order []string
+	for _, path := range paths {
+		project, err := LoadFile(path, Vars{Partial: true})
+		if err != nil {
+			continue
+		}
+		session := project.SessionNameOrDefault()
+		if _, seen := bySession[session]; !seen {
+			order = append(order, session)
+		}
+		bySession[session] = append(bySession[session], path)
+	}
+
+	var collisions []*SessionCollisionError
+	for _, session := range order {
+		if len(bySession[session]) > 1 {
+			collisions = append(collisions, &SessionCollisionError{Session: session, Paths: bySession[session]})
+		}
+	}
+	return collisions, nil
+}
+
+// FindSessionCollision returns the collision when another project file,
+// or the local project, uses project's session name, and nil otherwise.
+// Only files that could name the session are loaded: those whose name or
+// text contains it, and those whose session name may come from a
+// template or another file.
+func FindSessionCollision(project *Project) *SessionCollisionError {
+	session := project.SessionNameOrDefault()
+
+	files, err := DiscoverProjects()
+	if err != nil {
+		return nil
+	}
+	paths := make([]string, 0, len(files)+1)
+	if local, _ := FindLocalProject(""); local != "" {
+		paths = append(paths, local)
+	}
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	var matches []string
+	for _, path := range paths {
+		if path != project.Path && !mayNameSession(path, session) {
+			continue
+		}
+		candidate, err := LoadFile(path, Vars{Partial: true})
+		if err != nil {
+			continue
+		}
+		if candidate.SessionNameOrDefault() == session {
+			matches = append(matches, path)
+		}
+	}
+
+	if len(matches) < 2 {
+		return nil
+	}
+	return &SessionCollisionError{Session: session, Paths: matches}
+}
+
+// mayNameSession reports whether the project file at path could have the
+// session name session without loading it
+func mayNameSession(path string, session string) bool {
+	if projectNameFromPath(path) == session {
+		return true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(data, []byte(session)) || IsTemplate(data) ||
+		composeKeyPattern.Match(data)
+}
+
+// composeKeyPattern finds the keys that pull in other files
+var composeKeyPattern = regexp.MustCompile(`(?m)^(extends|include):`)
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindSessionCollision(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(t.TempDir(), "base.yml")
+	if err := os.WriteFile(base, []byte("session_name: dev\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", dir)
+	t.Setenv("ZELLIJINATOR_PATH", "")
+	chdir(t, dir)
+
+	files := map[string]string{
+		"api.yaml":     "name: api\nsession_name: dev\ntabs:\n  - name: main\n",
+		"web.yaml":     "name: web\nsession_name: dev\ntabs:\n  - name: main\n",
+		"docs.yaml":    "name: docs\ntabs:\n  - name: main\n",
+		"worker.yaml":  "extends: " + base + "\nname: worker\ntabs:\n  - name: main\n",
+		"dev.yaml":     "tabs:\n  - name: main\n",
+		"broken.yaml":  "name: [dev\n",
+		"staging.yaml": "name: staging\ntabs:\n  - name: main\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	load := func(name string) *Project {
+		t.Helper()
+		project, err := LoadFile(filepath.Join(dir, name), Vars{Partial: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return project
+	}
+
+	collision := FindSessionCollision(load("api.yaml"))
+	if collision == nil {
+		t.Fatal("expected a collision for dev")
+	}
+	var names []string
+	for _, path := range collision.Paths {
+		names = append(names, filepath.Base(path))
+	}
+	want := []string{"api.yaml", "dev.yaml", "web.yaml", "worker.yaml"}
+	if collision.Session != "dev" || !reflect.DeepEqual(names, want) {
+		t.Errorf("collision = %s %q, want dev %q", collision.Session, names, want)
+	}
+
+	if collision := FindSessionCollision(load("docs.yaml")); collision != nil {
+		t.Errorf("unexpected collision: %v", collision)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsFile overrides the location of the global settings file.
+// It is set from the --config flag.
+var SettingsFile string
+
+// Settings holds global zellijinator options read from config.yaml
+type Settings struct {
+	// SearchPaths are extra directories searched for projects after
+	// ConfigDir, e.g. a team-shared checkout
+	SearchPaths []string `yaml:"search_paths,omitempty"`
+
+	// Picker chooses the project picker: "builtin" (the default) or "fzf"
+	Picker string `yaml:"picker,omitempty"`
+
+	// DefaultTemplate is the template `here` builds its project from,
+	// "minimal" when unset
+	DefaultTemplate string `yaml:"default_template,omitempty"`
+}
+
+// SettingsPath returns the location of the global settings file
+func SettingsPath() string {
+	if SettingsFile != "" {
+		return ExpandPath(SettingsFile)
+	}
+	return filepath.Join(ConfigDir(), "config.yaml")
+}
+
+// LoadSettings reads the global settings file. A missing file yields
+// empty settings.
+func LoadSettings() (*Settings, error) {
+	settings := &Settings{}
+
+	data, err := os.ReadFile(SettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SearchPaths returns the directories searched for projects, highest
+// precedence first: ConfigDir, then the search_paths from config.yaml,
+// then the entries of $ZELLIJINATOR_PATH.
+func SearchPaths() []string {
+	paths := []string{ConfigDir()}
+
+	if settings, err := LoadSettings(); err == nil {
+		for _, p := range settings.SearchPaths {
+			paths = append(paths, ExpandPath(p))
+		}
+	}
+
+	for _, p := range filepath.SplitList(os.Getenv("ZELLIJINATOR_PATH")) {
+		if p != "" {
+			paths = append(paths, ExpandPath(p))
+		}
+	}
+
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		unique = append(unique, p)
+	}
+	return unique
+}
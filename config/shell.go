@@ -0,0 +1,35 @@
+package config
+
+// ShellOptions choose the shells a pane uses. They can be set on the
+// project, a tab or a pane; each level overrides the one above.
+type ShellOptions struct {
+	// Shell is the interactive shell a pane ends in, $SHELL by default.
+	// Wrappers work too, with the real shell in ShellArgs: direnv with
+	// [exec, ., zsh].
+	Shell     string   `yaml:"shell,omitempty"`
+	ShellArgs []string `yaml:"shell_args,omitempty"`
+	// CommandShell runs the pane's commands as <command_shell> -c
+	// <script>, sh by default
+	CommandShell CommandLine `yaml:"command_shell,omitempty"`
+}
+
+// Inherit returns the options with those not set taken from parent.
+// shell_args apply to the shell in effect: setting shell drops the
+// parent's shell_args, while setting only shell_args keeps its shell.
+func (o ShellOptions) Inherit(parent ShellOptions) ShellOptions {
+	if o.Shell == "" {
+		o.Shell = parent.Shell
+		if len(o.ShellArgs) == 0 {
+			o.ShellArgs = parent.ShellArgs
+		}
+	}
+	if len(o.CommandShell) == 0 {
+		o.CommandShell = parent.CommandShell
+	}
+	return o
+}
+
+// PaneShells returns the shell options that apply to a pane of a tab
+func (p *Project) PaneShells(tab Tab, pane Pane) ShellOptions {
+	return pane.ShellOptions.Inherit(tab.ShellOptions.Inherit(p.ShellOptions))
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShellOptionsInherit(t *testing.T) {
+	parent := ShellOptions{Shell: "zsh", ShellArgs: []string{"-l"}, CommandShell: CommandLine{"bash", "-e"}}
+
+	tests := []struct {
+		name    string
+		options ShellOptions
+		want    ShellOptions
+	}{
+		{name: "nothing set", want: parent},
+		{
+			name:    "shell drops the parent's args",
+			options: ShellOptions{Shell: "fish"},
+			want:    ShellOptions{Shell: "fish", CommandShell: CommandLine{"bash", "-e"}},
+		},
+		{
+			name:    "shell with args",
+			options: ShellOptions{Shell: "direnv", ShellArgs: []string{"exec", ".", "zsh"}},
+			want:    ShellOptions{Shell: "direnv", ShellArgs: []string{"exec", ".", "zsh"}, CommandShell: CommandLine{"bash", "-e"}},
+		},
+		{
+			name:    "only args keep the parent's shell",
+			options: ShellOptions{ShellArgs: []string{"-i"}},
+			want:    ShellOptions{Shell: "zsh", ShellArgs: []string{"-i"}, CommandShell: CommandLine{"bash", "-e"}},
+		},
+		{
+			name:    "command shell",
+			options: ShellOptions{CommandShell: CommandLine{"dash"}},
+			want:    ShellOptions{Shell: "zsh", ShellArgs: []string{"-l"}, CommandShell: CommandLine{"dash"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.Inherit(parent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inherit = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneShells(t *testing.T) {
+	project := parseProject(t, `
+shell: zsh
+shell_args: [-l]
+tabs:
+  - name: main
+    panes:
+      - name: default
+      - name: fish
+        shell: fish
+  - name: tools
+    command_shell: bash -e
+    shell_args: [-i]
+    panes:
+      - name: tab
+      - name: pane
+        command_shell: [dash]
+`)
+
+	tests := []struct {
+		tab, pane int
+		want      ShellOptions
+	}{
+		{0, 0, ShellOptions{Shell: "zsh", ShellArgs: []string{"-l"}}},
+		{0, 1, ShellOptions{Shell: "fish"}},
+		{1, 0, ShellOptions{Shell: "zsh", ShellArgs: []string{"-i"}, CommandShell: CommandLine{"bash", "-e"}}},
+		{1, 1, ShellOptions{Shell: "zsh", ShellArgs: []string{"-i"}, CommandShell: CommandLine{"dash"}}},
+	}
+
+	for _, tt := range tests {
+		tab := project.Tabs[tt.tab]
+		pane := tab.Panes[tt.pane]
+		if got := project.PaneShells(tab, pane); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PaneShells(%s, %s) = %+v, want %+v", tab.Name, pane.Name, got, tt.want)
+		}
+	}
+}
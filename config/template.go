@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Vars holds the runtime arguments given after the project name on the
+// command line, e.g. `zellijinator start proj 1234 branch=feature-x`
+type Vars struct {
+	// Args are the positional arguments, in order
+	Args []string
+	// Settings are the key=value arguments
+	Settings map[string]string
+	// Partial renders variables that weren't given as empty strings instead
+	// of failing, for commands that only inspect a project
+	Partial bool
+}
+
+// MissingVarError reports a template variable that was required but not
+// passed on the command line
+type MissingVarError struct {
+	Name string
+}
+
+func (e *MissingVarError) Error() string {
+	return fmt.Sprintf("missing required variable %q (pass it as %s=<value>)", e.Name, e.Name)
+}
+
+// ParseVars splits command line arguments into positional arguments and
+// key=value settings
+func ParseVars(args []string) Vars {
+	vars := Vars{Settings: make(map[string]string)}
+	for _, arg := range args {
+		if key, value, ok := strings.Cut(arg, "="); ok && key != "" {
+			vars.Settings[key] = value
+		} else {
+			vars.Args = append(vars.Args, arg)
+		}
+	}
+	return vars
+}
+
+// IsVarArg reports whether a command line argument is a key=value setting
+func IsVarArg(arg string) bool {
+	key, _, ok := strings.Cut(arg, "=")
+	return ok && key != ""
+}
+
+// TemplateDirective is the comment line that makes a project file a
+// template. Files without it are parsed as they are, so a literal {{ in a
+// command such as docker ps --format '{{.Names}}' needs no escaping.
+const TemplateDirective = "# zellijinator: template"
+
+var templateDirectivePattern = regexp.MustCompile(`(?m)^#\s*zellijinator:\s*template\s*$`)
+
+// IsTemplate reports whether a raw project file has the template directive
+func IsTemplate(data []byte) bool {
+	return templateDirectivePattern.Match(data)
+}
+
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]+)"`)
+
+var varReferencePattern = regexp.MustCompile(`(?:\b(?:var|required)\s+"([^"]+)"|\.Settings\.(\w+))`)
+
+// TemplateVarNames returns the key=value settings a project template
+// refers to, in the order they first appear
+func TemplateVarNames(data []byte) []string {
+	if !IsTemplate(data) {
+		return nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range varReferencePattern.FindAllStringSubmatch(string(data), -1) {
+		name := match[1] + match[2]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// RenderTemplate runs a raw project file through text/template before it
+// is parsed as YAML. Templates see .Args and .Settings from vars along with
+// the helper functions in templateFuncs. Files without TemplateDirective
+// are returned unchanged.
+func RenderTemplate(name string, data []byte, vars Vars) ([]byte, error) {
+	if !IsTemplate(data) {
+		return data, nil
+	}
+	if vars.Settings == nil {
+		vars.Settings = make(map[string]string)
+	}
+
+	missingKey := "missingkey=error"
+	if vars.Partial {
+		missingKey = "missingkey=zero"
+	}
+
+	tmpl, err := template.New(name).
+		Option(missingKey).
+		Funcs(templateFuncs(vars)).
+		Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, vars); err != nil {
+		var missing *MissingVarError
+		if errors.As(err, &missing) {
+			return nil, missing
+		}
+		if match := missingKeyPattern.FindStringSubmatch(err.Error()); match != nil {
+			return nil, &MissingVarError{Name: match[1]}
+		}
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// templateFuncs returns the functions available inside project templates
+func templateFuncs(vars Vars) template.FuncMap {
+	return template.FuncMap{
+		// arg returns the positional argument at index i, or ""
+		"arg": func(i int) string {
+			if i < 0 || i >= len(vars.Args) {
+				return ""
+			}
+			return vars.Args[i]
+		},
+		// var returns a key=value setting, or "" when it wasn't given
+		"var": func(key string) string {
+			return vars.Settings[key]
+		},
+		// required returns a key=value setting and fails when it wasn't given
+		"required": func(key string) (string, error) {
+			value, ok := vars.Settings[key]
+			if (!ok || value == "") && !vars.Partial {
+				return "", &MissingVarError{Name: key}
+			}
+			return value, nil
+		},
+		// default returns value, or fallback when value is empty.
+		// Written to be piped into: {{ var "branch" | default "main" }}
+		"default": func(fallback string, value string) string {
+			if value == "" {
+				return fallback
+			}
+			return value
+		},
+		"env": os.Getenv,
+		"cwd": func() string {
+			cwd, _ := os.Getwd()
+			return cwd
+		},
+		"cwd_basename": func() string {
+			cwd, _ := os.Getwd()
+			return filepath.Base(cwd)
+		},
+		"basename": filepath.Base,
+		"dirname":  filepath.Dir,
+		"hostname": func() string {
+			host, _ := os.Hostname()
+			return host
+		},
+		"user": func() string {
+			if user := os.Getenv("USER"); user != "" {
+				return user
+			}
+			return os.Getenv("USERNAME")
+		},
+		// git_branch returns the current branch of the repository in the
+		// working directory, or "" outside a repository
+		"git_branch": func() string {
+			out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+			if err != nil {
+				return ""
+			}
+			return strings.TrimSpace(string(out))
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	t.Setenv("ZELLIJINATOR_TEST_EDITOR", "hx")
+
+	tests := []struct {
+		name string
+		data string
+		args []string
+		want string
+		// missing is the variable reported missing, if rendering fails
+		missing string
+		partial bool
+	}{
+		{
+			name: "not a template",
+			data: "commands: [docker ps --format '{{.Names}}']\n",
+			want: "commands: [docker ps --format '{{.Names}}']\n",
+		},
+		{
+			name: "positional args",
+			data: "# zellijinator: template\nticket: {{ arg 0 }}-{{ index .Args 1 }}{{ arg 5 }}\n",
+			args: []string{"1234", "fix"},
+			want: "# zellijinator: template\nticket: 1234-fix\n",
+		},
+		{
+			name: "key=value vars",
+			data: "# zellijinator: template\nbranch: {{ var \"branch\" }} {{ .Settings.env }}\n",
+			args: []string{"branch=feature-x", "env=dev"},
+			want: "# zellijinator: template\nbranch: feature-x dev\n",
+		},
+		{
+			name: "default",
+			data: "# zellijinator: template\nbranch: {{ var \"branch\" | default \"main\" }}\n",
+			want: "# zellijinator: template\nbranch: main\n",
+		},
+		{
+			name: "default not used",
+			data: "# zellijinator: template\nbranch: {{ var \"branch\" | default \"main\" }}\n",
+			args: []string{"branch=dev"},
+			want: "# zellijinator: template\nbranch: dev\n",
+		},
+		{
+			name: "env",
+			data: "#zellijinator: template\neditor: {{ env \"ZELLIJINATOR_TEST_EDITOR\" }}\n",
+			want: "#zellijinator: template\neditor: hx\n",
+		},
+		{
+			name: "required",
+			data: "# zellijinator: template\nticket: {{ required \"ticket\" }}\n",
+			args: []string{"ticket=42"},
+			want: "# zellijinator: template\nticket: 42\n",
+		},
+		{
+			name:    "required missing",
+			data:    "# zellijinator: template\nticket: {{ required \"ticket\" }}\n",
+			missing: "ticket",
+		},
+		{
+			name:    "required empty",
+			data:    "# zellijinator: template\nticket: {{ required \"ticket\" }}\n",
+			args:    []string{"ticket="},
+			missing: "ticket",
+		},
+		{
+			name:    "missing setting",
+			data:    "# zellijinator: template\nbranch: {{ .Settings.branch }}\n",
+			missing: "branch",
+		},
+		{
+			name:    "partial",
+			data:    "# zellijinator: template\nticket: {{ required \"ticket\" }}{{ .Settings.branch }}\n",
+			partial: true,
+			want:    "# zellijinator: template\nticket: \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := ParseVars(tt.args)
+			vars.Partial = tt.partial
+
+			got, err := RenderTemplate("test", []byte(tt.data), vars)
+			if tt.missing != "" {
+				var missing *MissingVarError
+				if !errors.As(err, &missing) || missing.Name != tt.missing {
+					t.Fatalf("err = %v, want missing %q", err, tt.missing)
+				}
+				want := `missing required variable "` + tt.missing + `" (pass it as ` + tt.missing + `=<value>)`
+				if err.Error() != want {
+					t.Errorf("message = %q, want %q", err.Error(), want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("rendered =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTemplateInvalid(t *testing.T) {
+	if _, err := RenderTemplate("test", []byte("# zellijinator: template\nname: {{ if }}\n"), Vars{}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestTemplateVarNames(t *testing.T) {
+	data := "# zellijinator: template\na: {{ var \"branch\" }}\nb: {{ required \"ticket\" }}\nc: {{ .Settings.env }} {{ var \"branch\" }}\n"
+	if got, want := TemplateVarNames([]byte(data)), []string{"branch", "ticket", "env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %q, want %q", got, want)
+	}
+	if got := TemplateVarNames([]byte("a: {{ var \"branch\" }}\n")); got != nil {
+		t.Errorf("names without the directive = %q, want none", got)
+	}
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.yaml
+var builtinTemplates embed.FS
+
+// ErrTemplateNotFound is returned when no template has the requested name
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ProjectTemplate describes a template that new projects can be created from
+type ProjectTemplate struct {
+	Name string
+	// Path is the template file, empty for built-in templates
+	Path string
+	// Description is taken from the first comment line of the template
+	Description string
+	Builtin     bool
+	// Overrides is set when a user template replaces a built-in one
+	Overrides bool
+}
+
+// TemplateValues are the values a project template is rendered with
+type TemplateValues struct {
+	Name    string
+	Root    string
+	Answers map[string]string
+}
+
+// TemplatePrompt is a value a template asks the user for
+type TemplatePrompt struct {
+	Key      string
+	Question string
+	Default  string
+}
+
+// TemplateDirs returns the directories holding user templates, highest
+// precedence first: templates/ in ConfigDir, then in the XDG and legacy
+// config directories when those differ from it.
+func TemplateDirs() []string {
+	dirs := []string{filepath.Join(ConfigDir(), "templates")}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		xdgHome := os.Getenv("XDG_CONFIG_HOME")
+		if xdgHome == "" {
+			xdgHome = filepath.Join(home, ".config")
+		}
+		dirs = append(dirs,
+			filepath.Join(xdgHome, "zellijinator", "templates"),
+			filepath.Join(home, ".zellijinator", "templates"),
+		)
+	}
+
+	seen := make(map[string]bool)
+	unique := dirs[:0]
+	for _, dir := range dirs {
+		if !seen[dir] {
+			seen[dir] = true
+			unique = append(unique, dir)
+		}
+	}
+	return unique
+}
+
+// ListTemplates returns the user and built-in templates, sorted by name.
+// User templates take precedence over built-in ones with the same name.
+func ListTemplates() ([]ProjectTemplate, error) {
+	byName := make(map[string]ProjectTemplate)
+
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := builtinTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		byName[name] = ProjectTemplate{Name: name, Description: templateDescription(data), Builtin: true}
+	}
+
+	// Walk the directories lowest precedence first so higher ones win
+	dirs := TemplateDirs()
+	for i := len(dirs) - 1; i >= 0; i-- {
+		files, err := os.ReadDir(dirs[i])
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			for _, ext := range ProjectExtensions {
+				if !strings.HasSuffix(file.Name(), ext) {
+					continue
+				}
+				path := filepath.Join(dirs[i], file.Name())
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return nil, err
+				}
+				name := strings.TrimSuffix(file.Name(), ext)
+				_, exists := byName[name]
+				byName[name] = ProjectTemplate{
+					Name:        name,
+					Path:        path,
+					Description: templateDescription(data),
+					Overrides:   exists && byName[name].Builtin,
+				}
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	templates := make([]ProjectTemplate, 0, len(names))
+	for _, name := range names {
+		templates = append(templates, byName[name])
+	}
+	return templates, nil
+}
+
+// ReadTemplate returns the named template and its contents
+func ReadTemplate(name string) (*ProjectTemplate, []byte, error) {
+	templates, err := ListTemplates()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, tmpl := range templates {
+		if tmpl.Name != name {
+			continue
+		}
+		var data []byte
+		if tmpl.Builtin {
+			data, err = builtinTemplates.ReadFile("templates/" + name + ".yaml")
+		} else {
+			data, err = os.ReadFile(tmpl.Path)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return &tmpl, data, nil
+	}
+
+	return nil, nil, fmt.Errorf("%q: %w", name, ErrTemplateNotFound)
+}
+
+// TemplatePrompts returns the prompts in a template that values doesn't
+// answer yet, in the order they appear
+func TemplatePrompts(data []byte, values TemplateValues) ([]TemplatePrompt, error) {
+	var prompts []TemplatePrompt
+	if _, err := executeProjectTemplate(data, values, &prompts); err != nil {
+		return nil, err
+	}
+	return prompts, nil
+}
+
+// RenderProjectTemplate renders a project template. Templates use [[ ]]
+// delimiters so that {{ }} runtime placeholders pass through untouched:
+//
+//	name: [[ .Name | quote ]]
+//	root: [[ .Root | quote ]]
+//	panes:
+//	  - commands: [ [[ prompt "cmd" "Command to run" "make dev" | quote ]] ]
+//
+// Prompts that values doesn't answer use their default.
+func RenderProjectTemplate(data []byte, values TemplateValues) ([]byte, error) {
+	return executeProjectTemplate(data, values, nil)
+}
+
+func executeProjectTemplate(data []byte, values TemplateValues, prompts *[]TemplatePrompt) ([]byte, error) {
+	asked := make(map[string]bool)
+
+	funcs := template.FuncMap{
+		// prompt returns the answer for key, or the default when the user
+		// hasn't been asked yet
+		"prompt": func(key string, question string, defaults ...string) string {
+			if answer, ok := values.Answers[key]; ok {
+				return answer
+			}
+			def := ""
+			if len(defaults) > 0 {
+				def = defaults[0]
+			}
+			if prompts != nil && !asked[key] {
+				asked[key] = true
+				*prompts = append(*prompts, TemplatePrompt{Key: key, Question: question, Default: def})
+			}
+			return def
+		},
+		"split": func(s string, sep string) []string {
+			if s == "" {
+				return nil
+			}
+			return strings.Split(s, sep)
+		},
+		// quote makes a value a double-quoted YAML string, so answers
+		// such as "a: b" or "*" can't change the file's structure
+		"quote": strconv.Quote,
+		// shellquote makes a value a single shell word, for use inside
+		// commands: [[ .Name | shellquote | printf "echo %s" | quote ]]
+		"shellquote": ShellQuote,
+		"trim":       strings.TrimSpace,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"env":        os.Getenv,
+	}
+
+	tmpl, err := template.New("template").Delims("[[", "]]").Funcs(funcs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, values); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// templateDescription returns the text of the first comment line
+func templateDescription(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		}
+		return ""
+	}
+	return ""
+}
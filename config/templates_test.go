@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuiltinTemplatesQuoteValues(t *testing.T) {
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", t.TempDir())
+
+	tests := []struct {
+		template string
+		answers  map[string]string
+		// commands are the first command of each tab
+		commands []string
+	}{
+		{
+			template: "minimal",
+			commands: []string{`echo 'Welcome to Bob'\''s app: *!'`},
+		},
+		{
+			template: "monorepo",
+			answers:  map[string]string{"packages_dir": "my packages", "packages": "web, it's"},
+			commands: []string{"git status", `cd 'my packages/web'`, `cd 'my packages/it'\''s'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, data, err := ReadTemplate(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rendered, err := RenderProjectTemplate(data, TemplateValues{Name: "Bob's app: *", Root: "/tmp/a b", Answers: tt.answers})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var project Project
+			if err := yaml.Unmarshal(rendered, &project); err != nil {
+				t.Fatalf("rendered template doesn't parse: %v\n%s", err, rendered)
+			}
+			if project.Name != "Bob's app: *" || project.Root != "/tmp/a b" {
+				t.Errorf("name, root = %q, %q", project.Name, project.Root)
+			}
+
+			var commands []string
+			for _, tab := range project.Tabs {
+				commands = append(commands, tab.Panes[0].Commands[0])
+			}
+			if !reflect.DeepEqual(commands, tt.commands) {
+				t.Errorf("commands =\n%q\nwant\n%q", commands, tt.commands)
+			}
+			for _, command := range commands {
+				if err := exec.Command("sh", "-n", "-c", command).Run(); err != nil {
+					t.Errorf("%q isn't valid shell: %v", command, err)
+				}
+			}
+		})
+	}
+}
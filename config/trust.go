@@ -0,0 +1,102 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// trustFile records which local project files the user has approved.
+// Entries are keyed by absolute path and store the SourcesHash of the file
+// and everything it extends or includes at the time it was approved, so
+// any edit to one of them requires re-approval.
+type trustFile struct {
+	Trusted map[string]string `yaml:"trusted"`
+}
+
+// TrustStorePath returns the location of the local project allowlist
+func TrustStorePath() string {
+	return filepath.Join(ConfigDir(), "trusted.yaml")
+}
+
+// SourcesHash returns the hex encoded SHA-256 covering every file a
+// project was composed from. A file that extends and includes nothing
+// hashes to the SHA-256 of its contents.
+func SourcesHash(sources []SourceFile) string {
+	if len(sources) == 1 {
+		sum := sha256.Sum256(sources[0].Data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	h := sha256.New()
+	for _, source := range sources {
+		sum := sha256.Sum256(source.Data)
+		fmt.Fprintf(h, "%s\x00%x\n", source.Path, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IsTrusted reports whether the user has approved the project's file and
+// none of the files it was composed from have changed since. The hash is
+// taken from the contents the project was loaded from, so what was
+// checked is what runs.
+func IsTrusted(project *Project) (bool, error) {
+	abs, err := filepath.Abs(project.Path)
+	if err != nil {
+		return false, err
+	}
+
+	store, err := loadTrustFile()
+	if err != nil {
+		return false, err
+	}
+
+	return store.Trusted[abs] == SourcesHash(project.Sources), nil
+}
+
+// Trust adds the project, as it was loaded, to the allowlist
+func Trust(project *Project) error {
+	abs, err := filepath.Abs(project.Path)
+	if err != nil {
+		return err
+	}
+
+	store, err := loadTrustFile()
+	if err != nil {
+		return err
+	}
+	store.Trusted[abs] = SourcesHash(project.Sources)
+
+	if err := EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(store)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(TrustStorePath(), data, 0600)
+}
+
+func loadTrustFile() (*trustFile, error) {
+	store := &trustFile{}
+
+	data, err := os.ReadFile(TrustStorePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, store); err != nil {
+			return nil, err
+		}
+	}
+
+	if store.Trusted == nil {
+		store.Trusted = make(map[string]string)
+	}
+	return store, nil
+}
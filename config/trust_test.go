@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrustCoversEverySource(t *testing.T) {
+	t.Setenv("ZELLIJINATOR_CONFIG_DIR", t.TempDir())
+	repo := t.TempDir()
+	chdir(t, repo)
+
+	files := map[string]string{
+		".zellijinator.yml": "include: [shared.yml]\nlayout: dev.kdl\n",
+		"shared.yml":        "env:\n  A: b\n",
+		"dev.kdl":           "layout {\n    pane command=\"ls\"\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	load := func() *Project {
+		t.Helper()
+		project, err := LoadFile(filepath.Join(repo, ".zellijinator.yml"), Vars{Partial: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return project
+	}
+
+	project := load()
+	if len(project.Sources) != 3 {
+		t.Fatalf("sources = %d, want the project, its include and its layout", len(project.Sources))
+	}
+	if source := project.LayoutSource(); source == nil || string(source.Data) != files["dev.kdl"] {
+		t.Fatalf("layout source = %v", source)
+	}
+	if err := Trust(project); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"shared.yml", "dev.kdl"} {
+		t.Run(name, func(t *testing.T) {
+			if trusted, err := IsTrusted(load()); err != nil || !trusted {
+				t.Fatalf("trusted = %v, %v before changing %s", trusted, err, name)
+			}
+
+			path := filepath.Join(repo, name)
+			original := files[name]
+			t.Cleanup(func() { os.WriteFile(path, []byte(original), 0644) })
+			if err := os.WriteFile(path, []byte(original+"# changed\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if trusted, _ := IsTrusted(load()); trusted {
+				t.Errorf("still trusted after changing %s", name)
+			}
+		})
+	}
+}
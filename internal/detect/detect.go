@@ -0,0 +1,332 @@
+package detect
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding describes something recognised in the scanned directory
+type Finding struct {
+	File   string
+	Detail string
+}
+
+// Scan holds what was recognised in a directory
+type Scan struct {
+	Dir      string
+	Findings []Finding
+	// Warnings describe files that couldn't be read and were skipped
+	Warnings []string
+
+	// PackageManager is npm, yarn, pnpm or bun when package.json exists
+	PackageManager string
+	Scripts        map[string]string
+	MakeTargets    []string
+	Services       []string
+	Procs          []Proc
+	GoModule       bool
+	Cargo          bool
+	Git            bool
+}
+
+// Proc is a Procfile entry
+type Proc struct {
+	Name    string
+	Command string
+}
+
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.-]*)\s*:([^=]|$)`)
+
+// Directory inspects dir for the files that hint at how a project is run
+func Directory(dir string) (*Scan, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	scan := &Scan{Dir: dir}
+
+	// A file that can't be read or parsed is skipped, so the rest of the
+	// directory still informs the proposal
+	for _, source := range []func() error{scan.packageJSON, scan.makefile, scan.compose, scan.procfile} {
+		if err := source(); err != nil {
+			scan.Warnings = append(scan.Warnings, err.Error())
+		}
+	}
+
+	if exists(filepath.Join(dir, "go.mod")) {
+		scan.GoModule = true
+		scan.Findings = append(scan.Findings, Finding{File: "go.mod", Detail: "Go module"})
+	}
+	if exists(filepath.Join(dir, "Cargo.toml")) {
+		scan.Cargo = true
+		scan.Findings = append(scan.Findings, Finding{File: "Cargo.toml", Detail: "Rust crate"})
+	}
+	if exists(filepath.Join(dir, ".git")) {
+		scan.Git = true
+		scan.Findings = append(scan.Findings, Finding{File: ".git", Detail: "git repository"})
+	}
+
+	return scan, nil
+}
+
+// Project proposes a project for the scanned directory: an editor tab with
+// a test-runner pane, and a tab per Procfile entry or compose service.
+// Tab names are unique; a name already taken gets a -2, -3... suffix.
+func (s *Scan) Project(name string) *config.Project {
+	project := &config.Project{Name: name, Root: s.Dir}
+
+	used := make(map[string]bool)
+	addTab := func(tab config.Tab) {
+		base := tab.Name
+		for n := 2; used[tab.Name]; n++ {
+			tab.Name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[tab.Name] = true
+		project.Tabs = append(project.Tabs, tab)
+	}
+
+	editor := config.Tab{
+		Name:  "editor",
+		Focus: true,
+		Panes: []config.Pane{
+			{Focus: true, Commands: []string{"${EDITOR:-vi} ."}},
+		},
+	}
+	if test := s.TestCommand(); test != "" {
+		editor.Panes = append(editor.Panes, config.Pane{
+			Split:    "horizontal",
+			Size:     "30",
+			Commands: []string{test},
+		})
+	}
+	if s.Git {
+		editor.Panes = append(editor.Panes, config.Pane{
+			Split:    "vertical",
+			Size:     "40",
+			Commands: []string{"git status"},
+		})
+	}
+	addTab(editor)
+
+	for _, proc := range s.Procs {
+		addTab(config.Tab{
+			Name:  proc.Name,
+			Panes: []config.Pane{{Commands: []string{proc.Command}}},
+		})
+	}
+
+	for _, service := range s.Services {
+		addTab(config.Tab{
+			Name:  service,
+			Panes: []config.Pane{{Commands: []string{fmt.Sprintf("docker compose up %s", service)}}},
+		})
+	}
+
+	// Without a Procfile, run the dev server from package.json or the Makefile
+	if len(s.Procs) == 0 {
+		if dev := s.DevCommand(); dev != "" {
+			addTab(config.Tab{
+				Name:  "server",
+				Panes: []config.Pane{{Commands: []string{dev}}},
+			})
+		}
+	}
+
+	return project
+}
+
+// TestCommand returns the command that runs the test suite, if one was found
+func (s *Scan) TestCommand() string {
+	switch {
+	case s.Scripts["test"] != "":
+		return s.runScript("test")
+	case s.GoModule:
+		return "go test ./..."
+	case s.Cargo:
+		return "cargo test"
+	case s.hasMakeTarget("test"):
+		return "make test"
+	}
+	return ""
+}
+
+// DevCommand returns the command that starts a development server, if one
+// was found
+func (s *Scan) DevCommand() string {
+	for _, script := range []string{"dev", "start", "serve"} {
+		if s.Scripts[script] != "" {
+			return s.runScript(script)
+		}
+	}
+	for _, target := range []string{"dev", "run", "serve"} {
+		if s.hasMakeTarget(target) {
+			return "make " + target
+		}
+	}
+	return ""
+}
+
+func (s *Scan) runScript(script string) string {
+	if script == "test" && s.PackageManager == "npm" {
+		return "npm test"
+	}
+	if s.PackageManager == "npm" || s.PackageManager == "bun" {
+		return fmt.Sprintf("%s run %s", s.PackageManager, script)
+	}
+	return fmt.Sprintf("%s %s", s.PackageManager, script)
+}
+
+func (s *Scan) hasMakeTarget(target string) bool {
+	for _, t := range s.MakeTargets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scan) packageJSON() error {
+	data, err := os.ReadFile(filepath.Join(s.Dir, "package.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return fmt.Errorf("package.json: %w", err)
+	}
+	s.Scripts = pkg.Scripts
+
+	s.PackageManager = "npm"
+	for _, lock := range []struct{ file, manager string }{
+		{"yarn.lock", "yarn"},
+		{"pnpm-lock.yaml", "pnpm"},
+		{"bun.lockb", "bun"},
+	} {
+		if exists(filepath.Join(s.Dir, lock.file)) {
+			s.PackageManager = lock.manager
+			break
+		}
+	}
+
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.Findings = append(s.Findings, Finding{File: "package.json", Detail: fmt.Sprintf("%s scripts: %s", s.PackageManager, strings.Join(names, ", "))})
+	return nil
+}
+
+func (s *Scan) makefile() error {
+	file, err := os.Open(filepath.Join(s.Dir, "Makefile"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if match := makeTargetPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			if !s.hasMakeTarget(match[1]) {
+				s.MakeTargets = append(s.MakeTargets, match[1])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Makefile: %w", err)
+	}
+
+	s.Findings = append(s.Findings, Finding{File: "Makefile", Detail: fmt.Sprintf("targets: %s", strings.Join(s.MakeTargets, ", "))})
+	return nil
+}
+
+func (s *Scan) compose() error {
+	for _, name := range composeFiles {
+		data, err := os.ReadFile(filepath.Join(s.Dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		var compose struct {
+			Services map[string]yaml.Node `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		for service := range compose.Services {
+			s.Services = append(s.Services, service)
+		}
+		sort.Strings(s.Services)
+
+		s.Findings = append(s.Findings, Finding{File: name, Detail: fmt.Sprintf("services: %s", strings.Join(s.Services, ", "))})
+		return nil
+	}
+	return nil
+}
+
+func (s *Scan) procfile() error {
+	file, err := os.Open(filepath.Join(s.Dir, "Procfile"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, command, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		s.Procs = append(s.Procs, Proc{Name: strings.TrimSpace(name), Command: strings.TrimSpace(command)})
+		names = append(names, strings.TrimSpace(name))
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("Procfile: %w", err)
+	}
+
+	s.Findings = append(s.Findings, Finding{File: "Procfile", Detail: fmt.Sprintf("processes: %s", strings.Join(names, ", "))})
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,87 @@
+package detect
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDirectory(t *testing.T) {
+	tests := []struct {
+		dir            string
+		packageManager string
+		// tabs maps each tab name, in order, to its panes' first commands
+		tabs     [][]string
+		warnings []string
+	}{
+		{
+			dir:            "node",
+			packageManager: "yarn",
+			tabs: [][]string{
+				{"editor", "${EDITOR:-vi} .", "yarn test"},
+				{"server", "yarn dev"},
+			},
+		},
+		{
+			dir: "procfile-compose",
+			tabs: [][]string{
+				{"editor", "${EDITOR:-vi} ."},
+				{"web", "bin/rails server"},
+				{"editor-2", "bin/watch"},
+				{"db", "docker compose up db"},
+				{"web-2", "docker compose up web"},
+			},
+		},
+		{
+			dir: "go-make",
+			tabs: [][]string{
+				{"editor", "${EDITOR:-vi} .", "go test ./..."},
+				{"server", "make run"},
+			},
+		},
+		{
+			dir: "broken",
+			tabs: [][]string{
+				{"editor", "${EDITOR:-vi} ."},
+				{"server", "docker compose up server"},
+				{"server-2", "make dev"},
+			},
+			warnings: []string{"package.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			scan, err := Directory(filepath.Join("testdata", tt.dir))
+			if err != nil {
+				t.Fatalf("Directory: %v", err)
+			}
+
+			if scan.PackageManager != tt.packageManager {
+				t.Errorf("package manager = %q, want %q", scan.PackageManager, tt.packageManager)
+			}
+
+			var tabs [][]string
+			for _, tab := range scan.Project("test").Tabs {
+				got := []string{tab.Name}
+				for _, pane := range tab.Panes {
+					got = append(got, pane.Commands[0])
+				}
+				tabs = append(tabs, got)
+			}
+			if !reflect.DeepEqual(tabs, tt.tabs) {
+				t.Errorf("tabs =\n%q\nwant\n%q", tabs, tt.tabs)
+			}
+
+			if len(scan.Warnings) != len(tt.warnings) {
+				t.Fatalf("warnings = %q, want %d", scan.Warnings, len(tt.warnings))
+			}
+			for i, warning := range tt.warnings {
+				if !strings.Contains(scan.Warnings[i], warning) {
+					t.Errorf("warning %q doesn't mention %s", scan.Warnings[i], warning)
+				}
+			}
+		})
+	}
+}
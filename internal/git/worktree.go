@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a working tree of a repository
+type Worktree struct {
+	Path string
+	// Branch is the checked out branch without refs/heads/, empty when
+	// the HEAD is detached
+	Branch string
+	Head   string
+	Bare   bool
+}
+
+// ListWorktrees returns the worktrees of the repository containing dir,
+// the main worktree first
+func ListWorktrees(dir string) ([]Worktree, error) {
+	out, err := run(dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	var worktrees []Worktree
+	var current *Worktree
+	for _, line := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.Head = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		}
+	}
+	return worktrees, nil
+}
+
+// FindWorktree returns the worktree of the repository containing dir that
+// has name checked out, or whose directory is called name
+func FindWorktree(dir string, name string) (*Worktree, error) {
+	worktrees, err := ListWorktrees(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == name {
+			return &wt, nil
+		}
+	}
+	for _, wt := range worktrees {
+		if filepath.Base(wt.Path) == name || wt.Path == name {
+			return &wt, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddWorktree creates a worktree at path with branch checked out. The
+// branch is created from HEAD when it doesn't exist yet.
+func AddWorktree(dir string, path string, branch string) (*Worktree, error) {
+	args := []string{"worktree", "add", path, branch}
+	if !BranchExists(dir, branch) {
+		args = []string{"worktree", "add", "-b", branch, path}
+	}
+	if _, err := run(dir, args...); err != nil {
+		return nil, err
+	}
+	return &Worktree{Path: path, Branch: branch}, nil
+}
+
+// BranchExists reports whether branch exists locally, or on a remote
+// that git worktree add can track it from
+func BranchExists(dir string, branch string) bool {
+	if _, err := run(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return true
+	}
+	out, err := run(dir, "for-each-ref", "--format=%(refname)", "refs/remotes/*/"+branch)
+	return err == nil && out != ""
+}
+
+// Toplevel returns the root of the worktree containing dir
+func Toplevel(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
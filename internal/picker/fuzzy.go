@@ -0,0 +1,82 @@
+package picker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// match is an item that matched the filter, with the positions of the
+// matched characters for highlighting
+type match struct {
+	index     int
+	score     int
+	positions []int
+}
+
+// fuzzyMatch reports whether every character of pattern appears in text in
+// order, ignoring case. Matches at word starts and runs of consecutive
+// characters score higher; gaps and a late first match score lower.
+func fuzzyMatch(pattern string, text string) (int, []int, bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	want := []rune(strings.ToLower(pattern))
+
+	score := 0
+	positions := make([]int, 0, len(want))
+	last := -1
+	for _, r := range want {
+		found := -1
+		for i := last + 1; i < len(lower); i++ {
+			if lower[i] == r {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, nil, false
+		}
+
+		score += 10
+		switch {
+		case found == last+1 && last >= 0:
+			score += 15
+		case found == 0 || isBoundary(runes[found-1], runes[found]):
+			score += 10
+		default:
+			score -= found - last - 1
+		}
+		positions = append(positions, found)
+		last = found
+	}
+
+	score -= positions[0]
+	return score, positions, true
+}
+
+// isBoundary reports whether cur starts a word after prev
+func isBoundary(prev rune, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// filterItems returns the items matching pattern, best first. Ties keep
+// the original order, so an empty pattern leaves the list as given.
+func filterItems(items []Item, pattern string) []match {
+	var matches []match
+	for i, item := range items {
+		if score, positions, ok := fuzzyMatch(pattern, item.Name); ok {
+			matches = append(matches, match{index: i, score: score, positions: positions})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+	return matches
+}
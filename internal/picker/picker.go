@@ -0,0 +1,251 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dphaener/zellijinator/internal/styles"
+)
+
+// ErrCancelled is returned when the picker is closed without a choice
+var ErrCancelled = errors.New("picker cancelled")
+
+// Item is an entry in the picker
+type Item struct {
+	Name string
+	// Badge is shown after the name, e.g. the session state
+	Badge string
+	// Preview is shown beside the list while the item is highlighted
+	Preview string
+}
+
+// Action binds a key to something the caller does with the chosen item.
+// An action bound to "enter" replaces the default selection label.
+type Action struct {
+	Key  string
+	Name string
+}
+
+// Result is the chosen item and the name of the action whose key was
+// pressed. Action is empty when enter was pressed and isn't bound.
+type Result struct {
+	Item   Item
+	Action string
+}
+
+var (
+	selectedStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	matchStyle     = lipgloss.NewStyle().Underline(true)
+	activeBadge    = styles.Badge
+	exitedBadge    = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Background(lipgloss.Color("235")).Padding(0, 1)
+	previewStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	helpKeyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	helpLabelStyle = styles.Dim
+)
+
+// Run shows a full-screen fuzzy finder over items and returns the choice
+func Run(title string, items []Item, actions []Action) (Result, error) {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to filter"
+	input.Focus()
+
+	m := model{title: title, items: items, actions: actions, input: input}
+	m.matches = filterItems(items, "")
+
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := final.(model).result
+	if result == nil {
+		return Result{}, ErrCancelled
+	}
+	return *result, nil
+}
+
+type model struct {
+	title   string
+	items   []Item
+	actions []Action
+	input   textinput.Model
+	matches []match
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	result  *Result
+}
+
+func (m model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.scroll()
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			m.scroll()
+			return m, nil
+		}
+
+		for _, action := range m.actions {
+			if action.Key == key {
+				return m.choose(action.Name)
+			}
+		}
+		if key == "enter" {
+			return m.choose("")
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != previous {
+		m.matches = filterItems(m.items, m.input.Value())
+		m.cursor, m.offset = 0, 0
+	}
+	return m, cmd
+}
+
+// listHeight is the number of rows available for items
+func (m model) listHeight() int {
+	if m.height == 0 {
+		return 10
+	}
+	return max(m.height-5, 1)
+}
+
+// scroll keeps the cursor on screen
+func (m *model) scroll() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+func (m model) choose(action string) (tea.Model, tea.Cmd) {
+	if len(m.matches) == 0 {
+		return m, nil
+	}
+	m.result = &Result{Item: m.items[m.matches[m.cursor].index], Action: action}
+	return m, tea.Quit
+}
+
+func (m model) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.UnsetMarginBottom().Render(m.title))
+	b.WriteString(styles.Dim.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.items))))
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	listHeight := m.listHeight()
+
+	listWidth := 40
+	if m.width > 0 && m.width < 80 {
+		listWidth = m.width / 2
+	}
+
+	var rows []string
+	for i := m.offset; i < len(m.matches) && i < m.offset+listHeight; i++ {
+		rows = append(rows, m.renderRow(m.matches[i], i == m.cursor))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, styles.Dim.Render("  no matches"))
+	}
+	list := lipgloss.NewStyle().Width(listWidth).Height(listHeight).Render(strings.Join(rows, "\n"))
+
+	preview := ""
+	if len(m.matches) > 0 {
+		if text := m.items[m.matches[m.cursor].index].Preview; text != "" {
+			style := previewStyle.MaxHeight(listHeight)
+			if m.width > 0 {
+				style = style.Width(m.width - listWidth - 4)
+			}
+			preview = style.Render(text)
+		}
+	}
+
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, preview))
+	b.WriteString("\n")
+	b.WriteString(m.help())
+	return b.String()
+}
+
+func (m model) renderRow(match match, selected bool) string {
+	item := m.items[match.index]
+
+	matched := make(map[int]bool, len(match.positions))
+	for _, pos := range match.positions {
+		matched[pos] = true
+	}
+
+	base := lipgloss.NewStyle()
+	row := "  "
+	if selected {
+		base = selectedStyle
+		row = selectedStyle.Render("› ")
+	}
+	for i, r := range []rune(item.Name) {
+		if matched[i] {
+			row += base.Inherit(matchStyle).Render(string(r))
+		} else {
+			row += base.Render(string(r))
+		}
+	}
+
+	switch item.Badge {
+	case "":
+	case "active":
+		row += " " + activeBadge.Render("ACTIVE")
+	case "exited":
+		row += " " + exitedBadge.Render("EXITED")
+	default:
+		row += " " + styles.Dim.Render(item.Badge)
+	}
+	return row
+}
+
+func (m model) help() string {
+	enter := "select"
+	var parts []string
+	for _, action := range m.actions {
+		if action.Key == "enter" {
+			enter = action.Name
+			continue
+		}
+		parts = append(parts, helpKeyStyle.Render(action.Key)+" "+helpLabelStyle.Render(action.Name))
+	}
+	parts = append([]string{helpKeyStyle.Render("enter") + " " + helpLabelStyle.Render(enter)}, parts...)
+	parts = append(parts, helpKeyStyle.Render("esc")+" "+helpLabelStyle.Render("cancel"))
+	return strings.Join(parts, helpLabelStyle.Render(" • "))
+}
@@ -0,0 +1,132 @@
+package ready
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+)
+
+// pollInterval is how often the checks are retried
+const pollInterval = 500 * time.Millisecond
+
+// ErrTimeout is returned when a probe doesn't pass before its timeout
+var ErrTimeout = errors.New("timed out")
+
+// Wait blocks until every check of the probe passes or its timeout runs
+// out. Relative paths are resolved against the current directory. Only
+// log lines past the probe's offset count, so an old log doesn't make a
+// restarted service look ready; with no offset, the lines written after
+// Wait is called.
+func Wait(probe config.ReadyProbe) error {
+	deadline := time.Now().Add(probe.TimeoutDuration())
+
+	var log *logWatcher
+	if probe.Log != nil {
+		pattern, err := regexp.Compile(probe.Log.Match)
+		if err != nil {
+			return err
+		}
+		log = newLogWatcher(probe.Log.File, pattern, probe.Log.Offset)
+	}
+
+	for {
+		if check(probe, log) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// check runs the probe's checks once. The log check remembers a match,
+// so it isn't lost while the other checks catch up.
+func check(probe config.ReadyProbe, log *logWatcher) bool {
+	if log != nil && !log.scan() {
+		return false
+	}
+	if probe.Port != "" {
+		conn, err := net.DialTimeout("tcp", probe.Address(), pollInterval)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+	if probe.File != "" {
+		if _, err := os.Stat(config.ExpandPath(probe.File)); err != nil {
+			return false
+		}
+	}
+	if probe.Command != "" {
+		if err := exec.Command("sh", "-c", probe.Command).Run(); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// logWatcher follows a log file for a line matching pattern
+type logWatcher struct {
+	path    string
+	pattern *regexp.Regexp
+	offset  int64
+	matched bool
+}
+
+// newLogWatcher starts watching at offset, or at the current end of the
+// file when offset is negative
+func newLogWatcher(path string, pattern *regexp.Regexp, offset int64) *logWatcher {
+	w := &logWatcher{path: config.ExpandPath(path), pattern: pattern, offset: offset}
+	if offset < 0 {
+		w.offset = 0
+		if info, err := os.Stat(w.path); err == nil {
+			w.offset = info.Size()
+		}
+	}
+	return w
+}
+
+// scan reads the lines written since the last scan and reports whether
+// one has matched so far
+func (w *logWatcher) scan() bool {
+	if w.matched {
+		return true
+	}
+
+	f, err := os.Open(w.path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	// The file was truncated or replaced, start from the top
+	if info, err := f.Stat(); err == nil && info.Size() < w.offset {
+		w.offset = 0
+	}
+	if _, err := f.Seek(w.offset, io.SeekStart); err != nil {
+		return false
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		// Leave a partial last line to be read whole next time
+		if err != nil {
+			break
+		}
+		w.offset += int64(len(line))
+		if w.pattern.MatchString(line) {
+			w.matched = true
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package ready
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dphaener/zellijinator/config"
+)
+
+// appendLater appends text to path after a short delay, while Wait polls
+func appendLater(t *testing.T, path, text string) {
+	t.Helper()
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		f.WriteString(text)
+	}()
+}
+
+func TestWait(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "dev.log")
+	if err := os.WriteFile(log, []byte("booting\nlistening on :3000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		probe config.ReadyProbe
+		setup func()
+	}{
+		{
+			name:  "tcp",
+			probe: config.ReadyProbe{Port: listener.Addr().String()},
+		},
+		{
+			name:  "file created later",
+			probe: config.ReadyProbe{File: filepath.Join(dir, "migrated")},
+			setup: func() { appendLater(t, filepath.Join(dir, "migrated"), "") },
+		},
+		{
+			name:  "command",
+			probe: config.ReadyProbe{Command: "test -d " + config.ShellQuote(dir)},
+		},
+		{
+			name:  "log line before the offset",
+			probe: config.ReadyProbe{Log: &config.LogProbe{File: log, Match: `listening on :\d+`, Offset: 0}},
+		},
+		{
+			name:  "log line written later",
+			probe: config.ReadyProbe{Log: &config.LogProbe{File: log, Match: "migrations done", Offset: -1}},
+			setup: func() { appendLater(t, log, "migrations done\n") },
+		},
+		{
+			name: "every check",
+			probe: config.ReadyProbe{
+				Port:    listener.Addr().String(),
+				File:    log,
+				Command: "true",
+				Log:     &config.LogProbe{File: log, Match: "booting"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.probe.Timeout = "5s"
+			if tt.setup != nil {
+				tt.setup()
+			}
+			if err := Wait(tt.probe); err != nil {
+				t.Errorf("Wait = %v", err)
+			}
+		})
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "dev.log")
+	// The match is already in the log, but before the watcher starts
+	if err := os.WriteFile(log, []byte("ready\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		probe config.ReadyProbe
+	}{
+		{name: "command", probe: config.ReadyProbe{Command: "false"}},
+		{name: "file", probe: config.ReadyProbe{File: filepath.Join(dir, "missing")}},
+		{name: "old log line", probe: config.ReadyProbe{Log: &config.LogProbe{File: log, Match: "ready", Offset: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.probe.Timeout = "1s"
+			start := time.Now()
+			if err := Wait(tt.probe); !errors.Is(err, ErrTimeout) {
+				t.Fatalf("Wait = %v, want ErrTimeout", err)
+			}
+			if elapsed := time.Since(start); elapsed < time.Second {
+				t.Errorf("gave up after %s, before the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestWaitInvalidPattern(t *testing.T) {
+	probe := config.ReadyProbe{Log: &config.LogProbe{File: "dev.log", Match: "("}}
+	if err := Wait(probe); err == nil || errors.Is(err, ErrTimeout) {
+		t.Errorf("Wait = %v, want a pattern error", err)
+	}
+}
@@ -0,0 +1,99 @@
+package zellij
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dphaener/zellijinator/config"
+)
+
+// withDependencyWaits returns the project's tabs with a wait-ready command
+// in front of the commands of each pane that has depends_on, one for every
+// dependency with a ready probe. Each pane's shell options are resolved
+// from its tab and the project too. The project itself isn't changed.
+//
+// Log probes start at the log's size when the layout is generated, so a
+// ready line logged before the waiting pane starts, or before a native
+// pane is re-run, still counts.
+func withDependencyWaits(project *config.Project) []config.Tab {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "zellijinator"
+	}
+
+	tabs := make([]config.Tab, len(project.Tabs))
+	for i, tab := range project.Tabs {
+		tabs[i] = tab
+		tabs[i].Panes = make([]config.Pane, len(tab.Panes))
+		for j, pane := range tab.Panes {
+			var waits []string
+			for _, dep := range project.PaneDependencies(i, j) {
+				if dep.Ready != nil {
+					waits = append(waits, waitCommand(exe, project.ResolvedRoot(), dep.Name, *dep.Ready))
+				}
+			}
+			pane.ShellOptions = project.PaneShells(tab, pane)
+			if len(waits) > 0 {
+				pane = withWaits(pane, waits)
+			}
+			tabs[i].Panes[j] = pane
+		}
+	}
+	return tabs
+}
+
+// withWaits runs waits before the pane's commands. A native pane runs
+// them through sh and then execs its command, so Zellij still sees the
+// command's exit status and re-runs the waits with it. An edit pane opens
+// its file in $EDITOR the same way.
+func withWaits(pane config.Pane, waits []string) config.Pane {
+	if pane.Edit != "" {
+		pane.Mode = config.PaneModeNative
+		pane.Command = config.CommandLine{"sh", "-c", strings.Join(waits, " && ") + " && " + pane.EditorCommand()}
+		pane.Edit = ""
+		return pane
+	}
+
+	if pane.Native() {
+		quoted := make([]string, len(pane.Argv()))
+		for i, arg := range pane.Argv() {
+			quoted[i] = config.ShellQuote(arg)
+		}
+		script := strings.Join(waits, " && ") + " && exec " + strings.Join(quoted, " ")
+		pane.Mode = config.PaneModeNative
+		pane.Command = config.CommandLine{"sh", "-c", script}
+		pane.Commands = nil
+		return pane
+	}
+
+	pane.Commands = append(waits, pane.ShellCommands()...)
+	pane.Command = nil
+	return pane
+}
+
+// waitCommand returns the shell command that waits for a pane's probe.
+// Relative log files are found from root.
+func waitCommand(exe string, root string, name string, probe config.ReadyProbe) string {
+	args := []string{exe, "wait-ready", name}
+	flag := func(name string, value string) {
+		if value != "" {
+			args = append(args, "--"+name, value)
+		}
+	}
+	flag("port", probe.Port)
+	flag("file", probe.File)
+	flag("command", probe.Command)
+	if probe.Log != nil {
+		flag("log-file", probe.Log.File)
+		flag("log-match", probe.Log.Match)
+		flag("log-offset", strconv.FormatInt(config.LogOffset(root, probe.Log.File), 10))
+	}
+	flag("timeout", probe.Timeout)
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = config.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
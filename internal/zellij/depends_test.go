@@ -0,0 +1,128 @@
+package zellij
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dphaener/zellijinator/config"
+	"gopkg.in/yaml.v3"
+)
+
+// parseProject decodes a project from YAML without loading or validating it
+func parseProject(t *testing.T, data string) *config.Project {
+	t.Helper()
+	var project config.Project
+	if err := yaml.Unmarshal([]byte(data), &project); err != nil {
+		t.Fatal(err)
+	}
+	return &project
+}
+
+func TestWaitCommand(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "dev.log"), []byte("booting\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		probe config.ReadyProbe
+		want  string
+	}{
+		{
+			name:  "port",
+			probe: config.ReadyProbe{Port: "5432"},
+			want:  `'zj' 'wait-ready' 'db' '--port' '5432'`,
+		},
+		{
+			name:  "file, command and timeout",
+			probe: config.ReadyProbe{File: "tmp/migrated", Command: "pg_isready -q", Timeout: "2m"},
+			want:  `'zj' 'wait-ready' 'db' '--file' 'tmp/migrated' '--command' 'pg_isready -q' '--timeout' '2m'`,
+		},
+		{
+			name:  "log that exists",
+			probe: config.ReadyProbe{Log: &config.LogProbe{File: "dev.log", Match: `it's up on :\d+`}},
+			want:  `'zj' 'wait-ready' 'db' '--log-file' 'dev.log' '--log-match' 'it'\''s up on :\d+' '--log-offset' '8'`,
+		},
+		{
+			name:  "log that doesn't exist yet",
+			probe: config.ReadyProbe{Log: &config.LogProbe{File: "log/new.log", Match: "ready"}},
+			want:  `'zj' 'wait-ready' 'db' '--log-file' 'log/new.log' '--log-match' 'ready' '--log-offset' '0'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := waitCommand("zj", root, "db", tt.probe); got != tt.want {
+				t.Errorf("waitCommand =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDependencyWaits(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait := func(name string, port string) string {
+		return config.ShellQuote(exe) + " 'wait-ready' '" + name + "' '--port' '" + port + "'"
+	}
+
+	project := parseProject(t, `
+root: /srv/app
+tabs:
+  - name: main
+    panes:
+      - name: db
+        commands: [postgres]
+        ready:
+          port: "5432"
+      - name: cache
+        commands: [redis-server]
+      - name: migrate
+        depends_on: [db, cache]
+        commands: [bin/migrate, touch done]
+        ready:
+          port: "9000"
+      - name: server
+        depends_on: [migrate]
+        command: [bin/server, --port, "3000"]
+      - name: notes
+        depends_on: [db]
+        edit: NOTES.md:12
+`)
+	tabs := withDependencyWaits(project)
+
+	tests := []struct {
+		pane     int
+		commands []string
+		command  []string
+	}{
+		{pane: 0, commands: []string{"postgres"}},
+		// cache has no ready probe, so there is nothing to wait for
+		{pane: 2, commands: []string{wait("db", "5432"), "bin/migrate", "touch done"}},
+		// Waits for migrate's dependencies first, then execs the command
+		{pane: 3, command: []string{"sh", "-c", wait("db", "5432") + " && " + wait("migrate", "9000") + " && exec 'bin/server' '--port' '3000'"}},
+		{pane: 4, command: []string{"sh", "-c", wait("db", "5432") + ` && exec "${EDITOR:-vi}" +12 'NOTES.md'`}},
+	}
+
+	for _, tt := range tests {
+		pane := tabs[0].Panes[tt.pane]
+		if !reflect.DeepEqual(pane.Commands, tt.commands) || !reflect.DeepEqual([]string(pane.Command), tt.command) {
+			t.Errorf("pane %s:\ncommands %q\ncommand  %q\nwant\ncommands %q\ncommand  %q", pane.Name, pane.Commands, pane.Command, tt.commands, tt.command)
+		}
+	}
+
+	if server := tabs[0].Panes[3]; !server.Native() {
+		t.Error("server with waits is no longer a native pane")
+	}
+	if notes := tabs[0].Panes[4]; notes.Edit != "" || !notes.Native() {
+		t.Errorf("notes with waits should open its file from a native pane, got %+v", notes)
+	}
+	if len(project.Tabs[0].Panes[2].Commands) != 2 {
+		t.Error("the project's own panes were changed")
+	}
+}
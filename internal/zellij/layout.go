@@ -2,22 +2,24 @@ package zellij
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/dphaener/zellijinator/config"
 )
 
+// LayoutPresets are the predefined pane arrangements a tab can use
+var LayoutPresets = []string{"even-horizontal", "even-vertical", "main-vertical", "main-horizontal", "tiled"}
+
 // GenerateLayout creates a Zellij layout in KDL format from a project config
 func GenerateLayout(project *config.Project) string {
 	var layout strings.Builder
 
 	// Set the session name
-	sessionName := project.SessionName
-	if sessionName == "" {
-		sessionName = project.Name
-	}
-	layout.WriteString(fmt.Sprintf("session_name \"%s\"\n\n", sessionName))
+	sessionName := project.SessionNameOrDefault()
+	layout.WriteString(fmt.Sprintf("session_name %s\n\n", kdlString(sessionName)))
 
 	// If a default layout is specified, we need to structure it differently
 	// Zellij expects the layout to extend from a base layout
@@ -49,9 +51,12 @@ func GenerateLayout(project *config.Project) string {
 		layout.WriteString("    }\n\n")
 	}
 
+	rootDir := project.ResolvedRoot()
+	tabs := withDependencyWaits(project)
+
 	// Find the focused tab
 	focusedTabIndex := 0
-	for i, tab := range project.Tabs {
+	for i, tab := range tabs {
 		if tab.Focus {
 			focusedTabIndex = i
 			break
@@ -59,13 +64,13 @@ func GenerateLayout(project *config.Project) string {
 	}
 
 	// Generate tabs
-	for tabIndex, tab := range project.Tabs {
+	for tabIndex, tab := range tabs {
 		isFocusedTab := tabIndex == focusedTabIndex
 
-		layout.WriteString(fmt.Sprintf("    tab name=\"%s\"", tab.Name))
+		layout.WriteString(fmt.Sprintf("    tab name=%s", kdlString(tab.Name)))
 		
 		// Add cwd on the same line as tab declaration
-		layout.WriteString(fmt.Sprintf(" cwd=\"%s\"", project.Root))
+		layout.WriteString(fmt.Sprintf(" cwd=%s", kdlString(rootDir)))
 		
 		if isFocusedTab {
 			layout.WriteString(" focus=true")
@@ -75,10 +80,10 @@ func GenerateLayout(project *config.Project) string {
 		// Generate panes for this tab
 		if tab.Layout != "" {
 			// Use predefined layout
-			generatePanesWithLayout(&layout, tab.Layout, tab.Panes, project.Root, project.Env, "        ")
+			generatePanesWithLayout(&layout, tab.Layout, tab.Panes, rootDir, project.Env, "        ")
 		} else {
 			// Use manual layout from pane definitions
-			generatePanes(&layout, tab.Panes, project.Root, project.Env, "        ")
+			generatePanes(&layout, tab.Panes, rootDir, project.Env, "        ")
 		}
 
 		layout.WriteString("    }\n")
@@ -98,9 +103,7 @@ func writeSplitPane(layout *strings.Builder, pane *config.Pane, splitDir string,
 	layout.WriteString(" {\n")
 	
 	layout.WriteString(fmt.Sprintf("%s    pane", indent))
-	if pane.Focus {
-		layout.WriteString(" focus=true")
-	}
+	writePaneAttributes(layout, pane)
 	layout.WriteString(" {\n")
 	writePaneCommand(layout, pane, rootDir, envVars, indent+"        ")
 	layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -152,9 +155,7 @@ func generatePanesWithLayout(layout *strings.Builder, layoutType string, panes [
 			// Second pane
 			pane := panes[1]
 			layout.WriteString(fmt.Sprintf("%s    pane", indent))
-			if pane.Focus {
-				layout.WriteString(" focus=true")
-			}
+			writePaneAttributes(layout, &pane)
 			layout.WriteString(" {\n")
 			writePaneCommand(layout, &pane, rootDir, envVars, indent+"        ")
 			layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -183,9 +184,7 @@ func generatePanesWithLayout(layout *strings.Builder, layoutType string, panes [
 			// Second pane
 			pane := panes[1]
 			layout.WriteString(fmt.Sprintf("%s    pane", indent))
-			if pane.Focus {
-				layout.WriteString(" focus=true")
-			}
+			writePaneAttributes(layout, &pane)
 			layout.WriteString(" {\n")
 			writePaneCommand(layout, &pane, rootDir, envVars, indent+"        ")
 			layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -215,9 +214,7 @@ func generatePanesWithLayout(layout *strings.Builder, layoutType string, panes [
 			
 			// Top right pane
 			layout.WriteString(fmt.Sprintf("%s    pane", indent))
-			if panes[1].Focus {
-				layout.WriteString(" focus=true")
-			}
+			writePaneAttributes(layout, &panes[1])
 			layout.WriteString(" {\n")
 			writePaneCommand(layout, &panes[1], rootDir, envVars, indent+"        ")
 			layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -239,9 +236,7 @@ func generatePanesWithLayout(layout *strings.Builder, layoutType string, panes [
 			
 			// Bottom left
 			layout.WriteString(fmt.Sprintf("%s    pane", indent))
-			if panes[2].Focus {
-				layout.WriteString(" focus=true")
-			}
+			writePaneAttributes(layout, &panes[2])
 			layout.WriteString(" {\n")
 			writePaneCommand(layout, &panes[2], rootDir, envVars, indent+"        ")
 			layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -293,9 +288,7 @@ func generatePanes(layout *strings.Builder, panes []config.Pane, rootDir string,
 			
 			// Inside the container, create the actual pane with the command
 			layout.WriteString(fmt.Sprintf("%s    pane", indent))
-			if pane.Focus {
-				layout.WriteString(" focus=true")
-			}
+			writePaneAttributes(layout, &pane)
 			layout.WriteString(" {\n")
 			writePaneCommand(layout, &pane, rootDir, envVars, indent+"        ")
 			layout.WriteString(fmt.Sprintf("%s    }\n", indent))
@@ -311,9 +304,7 @@ func generatePanes(layout *strings.Builder, panes []config.Pane, rootDir string,
 func writePaneWithCommand(layout *strings.Builder, pane *config.Pane, rootDir string, envVars map[string]string, indent string) {
 	layout.WriteString(fmt.Sprintf("%spane", indent))
 	
-	if pane.Focus {
-		layout.WriteString(" focus=true")
-	}
+	writePaneAttributes(layout, pane)
 	
 	layout.WriteString(" {\n")
 	writePaneCommand(layout, pane, rootDir, envVars, indent+"    ")
@@ -321,40 +312,116 @@ func writePaneWithCommand(layout *strings.Builder, pane *config.Pane, rootDir st
 }
 
 func writePaneCommand(layout *strings.Builder, pane *config.Pane, rootDir string, envVars map[string]string, indent string) {
-	// Get the user's default shell from SHELL environment variable
-	userShell := os.Getenv("SHELL")
+	if pane.Native() {
+		writeNativeCommand(layout, pane.Argv(), indent)
+		return
+	}
+
+	// Edit panes without a line number use the edit attribute instead
+	if pane.Edit != "" {
+		if _, line := pane.EditTarget(); line > 0 {
+			writeNativeCommand(layout, []string{"sh", "-c", pane.EditorCommand()}, indent)
+		}
+		return
+	}
+
+	// The shell the pane ends in: its shell, else the user's default shell
+	// from the SHELL environment variable
+	userShell := pane.Shell
+	if userShell == "" {
+		userShell = os.Getenv("SHELL")
+	}
 	if userShell == "" {
 		userShell = "/bin/bash" // fallback to bash if SHELL is not set
 	}
-	
-	if len(pane.Commands) > 0 {
+	interactive := []string{shellWord(userShell)}
+	for _, arg := range pane.ShellArgs {
+		interactive = append(interactive, shellWord(arg))
+	}
+	execShell := strings.Join(interactive, " ")
+
+	// The commands are run by command_shell, sh by default as it is the
+	// most portable
+	commandShell := []string(pane.CommandShell)
+	if len(commandShell) == 0 {
+		commandShell = []string{"sh"}
+	}
+
+	commands := pane.ShellCommands()
+	if len(commands) > 0 {
 		// Prepare environment variables
 		envStr := ""
 		if len(envVars) > 0 {
 			var envPairs []string
-			for k, v := range envVars {
-				envPairs = append(envPairs, fmt.Sprintf("export %s='%s'", k, v))
+			for _, k := range slices.Sorted(maps.Keys(envVars)) {
+				envPairs = append(envPairs, fmt.Sprintf("export %s=%s", k, config.ShellQuote(envVars[k])))
 			}
 			envStr = strings.Join(envPairs, "; ") + "; "
 		}
 		
 		// Join all commands
-		commandStr := strings.Join(pane.Commands, " && ")
+		commandStr := strings.Join(commands, " && ")
 		
 		// Create the full command with environment and directory change
-		// Use the user's shell and exec to it after commands complete
-		fullCommand := fmt.Sprintf("%scd '%s' && %s; exec %s", envStr, rootDir, commandStr, userShell)
-		
-		// Escape quotes in command
-		fullCommand = strings.ReplaceAll(fullCommand, `"`, `\"`)
-		
-		// Use sh to run the command (more portable than bash)
-		layout.WriteString(fmt.Sprintf("%scommand \"sh\"\n", indent))
-		layout.WriteString(fmt.Sprintf("%sargs \"-c\" \"%s\"\n", indent, fullCommand))
+		// Use the pane's shell and exec to it after commands complete
+		fullCommand := fmt.Sprintf("%scd %s && %s; exec %s", envStr, config.ShellQuote(rootDir), commandStr, execShell)
+		writeNativeCommand(layout, append(commandShell, "-c", fullCommand), indent)
 	} else {
-		// If no commands, just start the user's shell in the right directory
-		fullCommand := fmt.Sprintf("cd '%s'; exec %s", rootDir, userShell)
-		layout.WriteString(fmt.Sprintf("%scommand \"sh\"\n", indent))
-		layout.WriteString(fmt.Sprintf("%sargs \"-c\" \"%s\"\n", indent, fullCommand))
+		// If no commands, just start the shell in the right directory
+		fullCommand := fmt.Sprintf("cd %s; exec %s", config.ShellQuote(rootDir), execShell)
+		writeNativeCommand(layout, append(commandShell, "-c", fullCommand), indent)
+	}
+}
+
+// shellWord quotes s for the shell unless it is plain enough not to need it
+func shellWord(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-+=/.,:@%") == "" {
+		return s
+	}
+	return config.ShellQuote(s)
+}
+
+// writeNativeCommand writes a command for Zellij to run directly, which
+// gets its environment from the session rather than a shell
+func writeNativeCommand(layout *strings.Builder, argv []string, indent string) {
+	if len(argv) == 0 {
+		return
+	}
+	layout.WriteString(fmt.Sprintf("%scommand %s\n", indent, kdlString(argv[0])))
+	if len(argv) > 1 {
+		args := make([]string, len(argv)-1)
+		for i, arg := range argv[1:] {
+			args[i] = kdlString(arg)
+		}
+		layout.WriteString(fmt.Sprintf("%sargs %s\n", indent, strings.Join(args, " ")))
+	}
+}
+
+// writePaneAttributes writes the name and focus of a pane after "pane"
+func writePaneAttributes(layout *strings.Builder, pane *config.Pane) {
+	if pane.Name != "" {
+		layout.WriteString(fmt.Sprintf(" name=%s", kdlString(pane.Name)))
+	}
+	if file, line := pane.EditTarget(); file != "" && line == 0 {
+		layout.WriteString(fmt.Sprintf(" edit=%s", kdlString(config.ExpandPath(file))))
+	}
+	if pane.Focus {
+		layout.WriteString(" focus=true")
+	}
+	if pane.Borderless {
+		layout.WriteString(" borderless=true")
 	}
-}
\ No newline at end of file
+	if pane.Native() {
+		if pane.CloseOnExit {
+			layout.WriteString(" close_on_exit=true")
+		}
+		if pane.StartSuspended {
+			layout.WriteString(" start_suspended=true")
+		}
+	}
+}
+
+// kdlString quotes s as a KDL string
+func kdlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
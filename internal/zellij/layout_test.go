@@ -0,0 +1,127 @@
+package zellij
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateLayoutQuoting(t *testing.T) {
+	project := parseProject(t, `
+name: api
+root: '/srv/a\b "c"'
+tabs:
+  - name: 'main "x"'
+  - name: 'C:\logs'
+`)
+	layout := GenerateLayout(project)
+
+	for _, want := range []string{
+		`session_name "api"`,
+		`    tab name="main \"x\"" cwd="/srv/a\\b \"c\"" focus=true {`,
+		`    tab name="C:\\logs" cwd="/srv/a\\b \"c\"" {`,
+	} {
+		if !strings.Contains(layout, want+"\n") {
+			t.Errorf("layout is missing the line\n%s\ngot\n%s", want, layout)
+		}
+	}
+}
+
+func TestGenerateLayoutPanes(t *testing.T) {
+	tests := []struct {
+		name string
+		pane string
+		// want is the pane as written in a tab of its own
+		want string
+	}{
+		{
+			name: "native command",
+			pane: `command: [bin/server, --name, 'my "app"']`,
+			want: `
+        pane {
+            command "bin/server"
+            args "--name" "my \"app\""
+        }
+`,
+		},
+		{
+			name: "native commands entry",
+			pane: "mode: native\ncommands: [\"tail -f 'log/dev.log'\"]",
+			want: `
+        pane {
+            command "tail"
+            args "-f" "log/dev.log"
+        }
+`,
+		},
+		{
+			name: "native options",
+			pane: "name: top\ncommand: top\nfocus: true\nborderless: true\nclose_on_exit: true\nstart_suspended: true",
+			want: `
+        pane name="top" focus=true borderless=true close_on_exit=true start_suspended=true {
+            command "top"
+        }
+`,
+		},
+		{
+			name: "edit",
+			pane: "name: docs\nedit: 'docs/a \"b\".md'\nborderless: true",
+			want: `
+        pane name="docs" edit="docs/a \"b\".md" borderless=true {
+        }
+`,
+		},
+		{
+			name: "edit at a line",
+			pane: "edit: src/main.go:12\nfocus: true",
+			want: `
+        pane focus=true {
+            command "sh"
+            args "-c" "exec \"${EDITOR:-vi}\" +12 'src/main.go'"
+        }
+`,
+		},
+		{
+			name: "shell commands",
+			pane: "commands: [bundle install, bin/rails server]",
+			want: `
+        pane {
+            command "sh"
+            args "-c" "export A='1'; export GREETING='it'\\''s me'; cd '/srv/it'\\''s app' && bundle install && bin/rails server; exec /bin/zsh"
+        }
+`,
+		},
+		{
+			name: "shell without commands",
+			pane: "name: shell",
+			want: `
+        pane name="shell" {
+            command "sh"
+            args "-c" "cd '/srv/it'\\''s app'; exec /bin/zsh"
+        }
+`,
+		},
+		{
+			name: "shell options",
+			pane: "commands: [make]\nshell: direnv\nshell_args: [exec, ., my shell]\ncommand_shell: bash -e",
+			want: `
+        pane {
+            command "bash"
+            args "-e" "-c" "export A='1'; export GREETING='it'\\''s me'; cd '/srv/it'\\''s app' && make; exec direnv exec . 'my shell'"
+        }
+`,
+		},
+	}
+
+	t.Setenv("SHELL", "/bin/zsh")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := parseProject(t, "name: api\nroot: /srv/it's app\nenv:\n  GREETING: it's me\n  A: '1'\ntabs:\n  - name: main\n    panes:\n      - "+
+				strings.ReplaceAll(tt.pane, "\n", "\n        "))
+			layout := GenerateLayout(project)
+			if !strings.Contains(layout, "{\n"+tt.want[1:]+"    }\n") {
+				t.Errorf("layout =\n%s\nwant the pane\n%s", layout, tt.want[1:])
+			}
+		})
+	}
+}
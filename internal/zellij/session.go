@@ -0,0 +1,90 @@
+package zellij
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session is a Zellij session as reported by list-sessions
+type Session struct {
+	Name string
+	// Exited is set for sessions that can be resurrected but aren't running
+	Exited bool
+	// Age is how long ago the session was created, zero if unknown
+	Age time.Duration
+}
+
+var (
+	createdPattern = regexp.MustCompile(`\[Created ([^\]]*) ago\]`)
+	agePartPattern = regexp.MustCompile(`(\d+)\s*(weeks?|days?|h|m|s)\b`)
+)
+
+// ListSessions returns the Zellij sessions. Lines look like
+// "name [Created 2h ago] (EXITED - attach to resurrect)".
+func ListSessions() ([]Session, error) {
+	output, err := exec.Command("zellij", "list-sessions", "-n").Output()
+	if err != nil {
+		// zellij exits non-zero when there are no sessions
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		sessions = append(sessions, Session{
+			Name:   fields[0],
+			Exited: strings.Contains(line, "EXITED"),
+			Age:    parseAge(line),
+		})
+	}
+	return sessions, nil
+}
+
+// FindSession returns the named session, or nil if there is none
+func FindSession(name string) *Session {
+	sessions, err := ListSessions()
+	if err != nil {
+		return nil
+	}
+	for _, session := range sessions {
+		if session.Name == name {
+			return &session
+		}
+	}
+	return nil
+}
+
+// parseAge reads the "[Created 1day 2h 5m ago]" part of a session line
+func parseAge(line string) time.Duration {
+	created := createdPattern.FindStringSubmatch(line)
+	if created == nil {
+		return 0
+	}
+
+	var age time.Duration
+	for _, part := range agePartPattern.FindAllStringSubmatch(created[1], -1) {
+		n, _ := strconv.Atoi(part[1])
+		unit := time.Second
+		switch {
+		case strings.HasPrefix(part[2], "week"):
+			unit = 7 * 24 * time.Hour
+		case strings.HasPrefix(part[2], "day"):
+			unit = 24 * time.Hour
+		case part[2] == "h":
+			unit = time.Hour
+		case part[2] == "m":
+			unit = time.Minute
+		}
+		age += time.Duration(n) * unit
+	}
+	return age
+}